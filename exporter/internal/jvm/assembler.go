@@ -0,0 +1,443 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const defaultAssemblyMaxStaleness = 5 * time.Minute
+
+// AssemblyConfig defines how metrics of the same JVM that arrive in different batches are
+// assembled into one snapshot before conversion.
+type AssemblyConfig struct {
+	// Interval is how often one snapshot per JVM is converted and sent. Only JVMs that reported
+	// data during the interval are sent. 0 disables assembly and every batch is sent as it arrives.
+	// Snapshots bypass sending_queue and are retried as configured by retry_on_failure.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// MaxStaleness is how long the last value of a series keeps being included in snapshots
+	// after it stopped arriving.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
+
+func NewDefaultAssemblyConfig() AssemblyConfig {
+	return AssemblyConfig{
+		MaxStaleness: defaultAssemblyMaxStaleness,
+	}
+}
+
+// Validate checks the assembly configuration.
+func (cfg *AssemblyConfig) Validate() error {
+	if cfg.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if cfg.Interval > 0 && cfg.MaxStaleness < cfg.Interval {
+		return errors.New("max_staleness must not be shorter than interval")
+	}
+	return nil
+}
+
+// assembledMetric 保存一个指标的描述和各序列最新的数据点，series 与数据点按下标一一对应。
+// delta 数据点在两次发送之间全部保留，发送后清空
+type assembledMetric struct {
+	metric   pmetric.Metric
+	series   []string
+	received []time.Time
+	deltas   int
+}
+
+// set 用 src 的第 i 个数据点替换同一序列的数据点，不存在时追加
+func (m *assembledMetric) set(src pmetric.Metric, i int, series string, now time.Time) {
+	index := -1
+	for k, s := range m.series {
+		if s == series {
+			index = k
+			break
+		}
+	}
+	if index < 0 {
+		appendDataPoint(m.metric, src, i)
+		m.series = append(m.series, series)
+		m.received = append(m.received, now)
+		return
+	}
+	copyDataPoint(m.metric, index, src, i)
+	m.received[index] = now
+}
+
+// expire 删除超过 maxStaleness 未更新的数据点，all 为 true 时删除全部数据点
+func (m *assembledMetric) expire(now time.Time, maxStaleness time.Duration, all bool) {
+	keep := make([]bool, len(m.series))
+	series, received := m.series[:0], m.received[:0]
+	for k := range keep {
+		keep[k] = !all && now.Sub(m.received[k]) <= maxStaleness
+		if keep[k] {
+			series = append(series, m.series[k])
+			received = append(received, m.received[k])
+		}
+	}
+	m.series, m.received = series, received
+	removeDataPoints(m.metric, keep)
+}
+
+// assembledScope 保存一个 instrumentation scope 下的指标，names 保持指标首次出现的顺序
+type assembledScope struct {
+	scope   pcommon.InstrumentationScope
+	metrics map[string]*assembledMetric
+	names   []string
+}
+
+// assembledJVM 保存单个 JVM 在多个批次中收到的指标
+type assembledJVM struct {
+	resource pcommon.Resource
+	scopes   map[string]*assembledScope
+	order    []string
+	// updated 表示上次发送后是否收到过新数据
+	updated bool
+}
+
+// MetricFilter selects the metrics the assembler keeps. Converter implements it.
+type MetricFilter interface {
+	MatchScope(name string) bool
+	MatchMetric(metric pmetric.Metric) bool
+}
+
+// Assembler 按 JVM 缓存多个批次的指标，每个周期为每个 JVM 生成一份完整的快照
+type Assembler struct {
+	cfg    AssemblyConfig
+	filter MetricFilter
+	flush  func(context.Context, pmetric.Metrics) error
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	jvms map[string]*assembledJVM
+
+	stop chan struct{}
+	done chan struct{}
+	// cancel 中止后台循环中正在重试的发送
+	cancel context.CancelFunc
+}
+
+// NewAssembler creates an assembler. flush is called with one snapshot per interval and is
+// responsible for retrying it, the assembler does not keep snapshots that failed to send.
+func NewAssembler(cfg AssemblyConfig, filter MetricFilter, flush func(context.Context, pmetric.Metrics) error, logger *zap.Logger) *Assembler {
+	return &Assembler{
+		cfg:    cfg,
+		filter: filter,
+		flush:  flush,
+		logger: logger,
+		jvms:   make(map[string]*assembledJVM),
+	}
+}
+
+// Start 启动按周期发送快照的后台循环
+func (a *Assembler) Start() {
+	// Shutdown 会清空 a.stop，后台循环使用局部变量
+	stop := make(chan struct{})
+	a.stop = stop
+	a.done = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	go func() {
+		defer close(a.done)
+		ticker := time.NewTicker(a.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.send(ctx, time.Now())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown 停止后台循环并发送最后一份快照
+func (a *Assembler) Shutdown(ctx context.Context) error {
+	if a.stop == nil {
+		return nil
+	}
+	// 先清空 stop，等待超时后再次调用 Shutdown 不会重复关闭
+	close(a.stop)
+	a.stop = nil
+	a.cancel()
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.send(ctx, time.Now())
+}
+
+func (a *Assembler) send(ctx context.Context, now time.Time) error {
+	md := a.snapshot(now)
+	if md.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	err := a.flush(ctx, md)
+	if err != nil {
+		a.logger.Warn("Failed to send assembled JVM metrics",
+			zap.Int("jvms", md.ResourceMetrics().Len()), zap.Error(err))
+	}
+	return err
+}
+
+// Add 将批次中需要转换的指标合并到各 JVM 的缓存中，其他指标不缓存
+func (a *Assembler) Add(md pmetric.Metrics, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		resourceMetric := resourceMetrics.At(i)
+		var jvm *assembledJVM
+		scopeMetrics := resourceMetric.ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			scopeMetric := scopeMetrics.At(j)
+			if !a.filter.MatchScope(scopeMetric.Scope().Name()) {
+				continue
+			}
+			var scope *assembledScope
+			metrics := scopeMetric.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if !a.filter.MatchMetric(metrics.At(k)) {
+					continue
+				}
+				if jvm == nil {
+					jvm = a.jvm(resourceMetric.Resource())
+				}
+				if scope == nil {
+					scope = jvm.scope(scopeMetric.Scope())
+				}
+				scope.add(metrics.At(k), now)
+			}
+		}
+	}
+}
+
+// jvm 返回资源对应的缓存，资源属性以最新批次为准
+func (a *Assembler) jvm(resource pcommon.Resource) *assembledJVM {
+	key := ResourceKey(resource.Attributes())
+	jvm, ok := a.jvms[key]
+	if !ok {
+		jvm = &assembledJVM{
+			resource: pcommon.NewResource(),
+			scopes:   make(map[string]*assembledScope),
+		}
+		a.jvms[key] = jvm
+	}
+	resource.CopyTo(jvm.resource)
+	jvm.updated = true
+	return jvm
+}
+
+func (j *assembledJVM) scope(instrumentationScope pcommon.InstrumentationScope) *assembledScope {
+	scopeKey := instrumentationScope.Name() + "@" + instrumentationScope.Version()
+	scope, ok := j.scopes[scopeKey]
+	if !ok {
+		scope = &assembledScope{
+			scope:   pcommon.NewInstrumentationScope(),
+			metrics: make(map[string]*assembledMetric),
+		}
+		instrumentationScope.CopyTo(scope.scope)
+		j.scopes[scopeKey] = scope
+		j.order = append(j.order, scopeKey)
+	}
+	return scope
+}
+
+func (s *assembledScope) add(metric pmetric.Metric, now time.Time) {
+	assembled, ok := s.metrics[metric.Name()]
+	// 指标类型或聚合方式变化时丢弃旧的序列
+	if ok && (assembled.metric.Type() != metric.Type() || isDeltaMetric(assembled.metric) != isDeltaMetric(metric)) {
+		ok = false
+	}
+	if !ok {
+		if _, exists := s.metrics[metric.Name()]; !exists {
+			s.names = append(s.names, metric.Name())
+		}
+		assembled = &assembledMetric{metric: pmetric.NewMetric()}
+		copyMetricDescriptor(assembled.metric, metric)
+		s.metrics[metric.Name()] = assembled
+	}
+	delta := isDeltaMetric(metric)
+	for i := 0; i < dataPointCount(metric); i++ {
+		var series string
+		if delta {
+			series = strconv.Itoa(assembled.deltas)
+			assembled.deltas++
+		} else {
+			series = attributesKey(dataPointAttributes(metric, i))
+		}
+		assembled.set(metric, i, series, now)
+	}
+}
+
+// snapshot 为上次发送后收到过数据的 JVM 生成快照，超过 MaxStaleness 的序列被丢弃
+func (a *Assembler) snapshot(now time.Time) pmetric.Metrics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	md := pmetric.NewMetrics()
+	keys := make([]string, 0, len(a.jvms))
+	for key := range a.jvms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		jvm := a.jvms[key]
+		jvm.expire(now, a.cfg.MaxStaleness)
+		if len(jvm.scopes) == 0 {
+			delete(a.jvms, key)
+			continue
+		}
+		if !jvm.updated {
+			continue
+		}
+		jvm.updated = false
+		resourceMetric := md.ResourceMetrics().AppendEmpty()
+		jvm.resource.CopyTo(resourceMetric.Resource())
+		for _, scopeKey := range jvm.order {
+			scope := jvm.scopes[scopeKey]
+			scopeMetric := resourceMetric.ScopeMetrics().AppendEmpty()
+			scope.scope.CopyTo(scopeMetric.Scope())
+			for _, name := range scope.names {
+				assembled := scope.metrics[name]
+				assembled.metric.CopyTo(scopeMetric.Metrics().AppendEmpty())
+				// delta 数据点只发送一次
+				if isDeltaMetric(assembled.metric) {
+					assembled.expire(now, a.cfg.MaxStaleness, true)
+				}
+			}
+		}
+	}
+	return md
+}
+
+// expire 删除超过 maxStaleness 未更新的序列，以及因此变空的指标和 scope
+func (j *assembledJVM) expire(now time.Time, maxStaleness time.Duration) {
+	order := j.order[:0]
+	for _, scopeKey := range j.order {
+		scope := j.scopes[scopeKey]
+		names := scope.names[:0]
+		for _, name := range scope.names {
+			assembled := scope.metrics[name]
+			assembled.expire(now, maxStaleness, false)
+			if dataPointCount(assembled.metric) == 0 {
+				delete(scope.metrics, name)
+				continue
+			}
+			names = append(names, name)
+		}
+		scope.names = names
+		if len(scope.names) == 0 {
+			delete(j.scopes, scopeKey)
+			continue
+		}
+		order = append(order, scopeKey)
+	}
+	j.order = order
+}
+
+// copyMetricDescriptor 复制指标的名称、单位、类型和聚合方式，不复制数据点
+func copyMetricDescriptor(dst, src pmetric.Metric) {
+	dst.SetName(src.Name())
+	dst.SetDescription(src.Description())
+	dst.SetUnit(src.Unit())
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		dst.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		dst.SetEmptySum().SetAggregationTemporality(src.Sum().AggregationTemporality())
+		dst.Sum().SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dst.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dst.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dst.SetEmptySummary()
+	}
+}
+
+func dataPointCount(metric pmetric.Metric) int {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		return numberDataPoints(metric).Len()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return metric.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+func dataPointAttributes(metric pmetric.Metric, i int) pcommon.Map {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		return numberDataPoints(metric).At(i).Attributes()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().DataPoints().At(i).Attributes()
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().DataPoints().At(i).Attributes()
+	case pmetric.MetricTypeSummary:
+		return metric.Summary().DataPoints().At(i).Attributes()
+	}
+	return pcommon.NewMap()
+}
+
+// appendDataPoint 将 src 的第 i 个数据点追加到 dst
+func appendDataPoint(dst, src pmetric.Metric, i int) {
+	switch src.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		numberDataPoints(src).At(i).CopyTo(numberDataPoints(dst).AppendEmpty())
+	case pmetric.MetricTypeHistogram:
+		src.Histogram().DataPoints().At(i).CopyTo(dst.Histogram().DataPoints().AppendEmpty())
+	case pmetric.MetricTypeExponentialHistogram:
+		src.ExponentialHistogram().DataPoints().At(i).CopyTo(dst.ExponentialHistogram().DataPoints().AppendEmpty())
+	case pmetric.MetricTypeSummary:
+		src.Summary().DataPoints().At(i).CopyTo(dst.Summary().DataPoints().AppendEmpty())
+	}
+}
+
+// copyDataPoint 用 src 的第 i 个数据点覆盖 dst 的第 index 个数据点
+func copyDataPoint(dst pmetric.Metric, index int, src pmetric.Metric, i int) {
+	switch src.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		numberDataPoints(src).At(i).CopyTo(numberDataPoints(dst).At(index))
+	case pmetric.MetricTypeHistogram:
+		src.Histogram().DataPoints().At(i).CopyTo(dst.Histogram().DataPoints().At(index))
+	case pmetric.MetricTypeExponentialHistogram:
+		src.ExponentialHistogram().DataPoints().At(i).CopyTo(dst.ExponentialHistogram().DataPoints().At(index))
+	case pmetric.MetricTypeSummary:
+		src.Summary().DataPoints().At(i).CopyTo(dst.Summary().DataPoints().At(index))
+	}
+}
+
+// removeDataPoints 删除 keep 中为 false 的数据点
+func removeDataPoints(metric pmetric.Metric, keep []bool) {
+	index := 0
+	remove := func() bool {
+		removed := !keep[index]
+		index++
+		return removed
+	}
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		numberDataPoints(metric).RemoveIf(func(pmetric.NumberDataPoint) bool { return remove() })
+	case pmetric.MetricTypeHistogram:
+		metric.Histogram().DataPoints().RemoveIf(func(pmetric.HistogramDataPoint) bool { return remove() })
+	case pmetric.MetricTypeExponentialHistogram:
+		metric.ExponentialHistogram().DataPoints().RemoveIf(func(pmetric.ExponentialHistogramDataPoint) bool { return remove() })
+	case pmetric.MetricTypeSummary:
+		metric.Summary().DataPoints().RemoveIf(func(pmetric.SummaryDataPoint) bool { return remove() })
+	}
+}
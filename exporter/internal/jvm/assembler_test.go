@@ -0,0 +1,258 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// testFilter 只保留列出的 scope 和指标
+type testFilter struct {
+	scopes  []string
+	metrics []string
+}
+
+func (f testFilter) MatchScope(name string) bool {
+	for _, scope := range f.scopes {
+		if scope == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f testFilter) MatchMetric(metric pmetric.Metric) bool {
+	for _, name := range f.metrics {
+		if name == metric.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// assemblerPoint 是测试批次中的一个 Sum 数据点
+type assemblerPoint struct {
+	instance string
+	scope    string
+	name     string
+	delta    bool
+	series   string
+	value    int64
+}
+
+func newAssemblerBatch(points []assemblerPoint) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, p := range points {
+		resourceMetric := md.ResourceMetrics().AppendEmpty()
+		resourceMetric.Resource().Attributes().PutStr(SERVICE_INSTANCE_ID, p.instance)
+		scopeMetric := resourceMetric.ScopeMetrics().AppendEmpty()
+		scopeMetric.Scope().SetName(p.scope)
+		metric := scopeMetric.Metrics().AppendEmpty()
+		metric.SetName(p.name)
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		if p.delta {
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		}
+		dataPoint := sum.DataPoints().AppendEmpty()
+		dataPoint.Attributes().PutStr(JVM_MEMORY_POOL_NAME, p.series)
+		dataPoint.SetIntValue(p.value)
+	}
+	return md
+}
+
+// snapshotValues 按 service.instance.id 和指标名返回快照中的数据点值
+func snapshotValues(md pmetric.Metrics) map[string]map[string][]int64 {
+	values := make(map[string]map[string][]int64)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		resourceMetric := md.ResourceMetrics().At(i)
+		instance, _ := resourceMetric.Resource().Attributes().Get(SERVICE_INSTANCE_ID)
+		metrics := make(map[string][]int64)
+		for j := 0; j < resourceMetric.ScopeMetrics().Len(); j++ {
+			scopeMetrics := resourceMetric.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < scopeMetrics.Len(); k++ {
+				dataPoints := numberDataPoints(scopeMetrics.At(k))
+				for l := 0; l < dataPoints.Len(); l++ {
+					metrics[scopeMetrics.At(k).Name()] = append(metrics[scopeMetrics.At(k).Name()], dataPoints.At(l).IntValue())
+				}
+			}
+		}
+		values[instance.AsString()] = metrics
+	}
+	return values
+}
+
+func TestAssemblerSnapshot(t *testing.T) {
+	const scope = "io.opentelemetry.runtime-telemetry-java17"
+	filter := testFilter{scopes: []string{scope}, metrics: []string{JVM_MEMORY_USED, JVM_CLASS_LOADED}}
+	start := time.Unix(1700000000, 0)
+
+	type step struct {
+		at       time.Duration
+		add      []assemblerPoint
+		snapshot bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		want  []map[string]map[string][]int64
+	}{
+		{
+			name: "latest value of a series wins",
+			steps: []step{
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 1}}},
+				{at: time.Second, add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 2}}},
+				{at: 2 * time.Second, snapshot: true},
+			},
+			want: []map[string]map[string][]int64{
+				{"a": {JVM_MEMORY_USED: {2}}},
+			},
+		},
+		{
+			name: "series and JVMs from different batches are combined",
+			steps: []step{
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 1}}},
+				{add: []assemblerPoint{
+					{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "old", value: 2},
+					{instance: "b", scope: scope, name: JVM_CLASS_LOADED, series: "", value: 3},
+				}},
+				{snapshot: true},
+			},
+			want: []map[string]map[string][]int64{
+				{"a": {JVM_MEMORY_USED: {1, 2}}, "b": {JVM_CLASS_LOADED: {3}}},
+			},
+		},
+		{
+			name: "metrics that are not converted are not kept",
+			steps: []step{
+				{add: []assemblerPoint{
+					{instance: "a", scope: "", name: JVM_MEMORY_USED, series: "eden", value: 1},
+					{instance: "a", scope: "io.opentelemetry.jdbc", name: JVM_MEMORY_USED, series: "eden", value: 1},
+					{instance: "a", scope: scope, name: "http.server.request.duration", value: 1},
+				}},
+				{snapshot: true},
+			},
+			want: []map[string]map[string][]int64{{}},
+		},
+		{
+			name: "delta points are sent once",
+			steps: []step{
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_CLASS_LOADED, delta: true, value: 1}}},
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_CLASS_LOADED, delta: true, value: 2}}},
+				{snapshot: true},
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 5}}},
+				{snapshot: true},
+			},
+			want: []map[string]map[string][]int64{
+				{"a": {JVM_CLASS_LOADED: {1, 2}}},
+				{"a": {JVM_MEMORY_USED: {5}}},
+			},
+		},
+		{
+			name: "JVMs without new data are not sent",
+			steps: []step{
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 1}}},
+				{snapshot: true},
+				{at: time.Minute, snapshot: true},
+			},
+			want: []map[string]map[string][]int64{
+				{"a": {JVM_MEMORY_USED: {1}}},
+				{},
+			},
+		},
+		{
+			name: "stale series are dropped",
+			steps: []step{
+				{add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 1}}},
+				{at: 4 * time.Minute, add: []assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "old", value: 2}}},
+				{at: 6 * time.Minute, snapshot: true},
+			},
+			want: []map[string]map[string][]int64{
+				{"a": {JVM_MEMORY_USED: {2}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := AssemblyConfig{Interval: time.Minute, MaxStaleness: 5 * time.Minute}
+			assembler := NewAssembler(cfg, filter, nil, zap.NewNop())
+			var got []map[string]map[string][]int64
+			for _, s := range tt.steps {
+				if len(s.add) > 0 {
+					assembler.Add(newAssemblerBatch(s.add), start.Add(s.at))
+				}
+				if s.snapshot {
+					got = append(got, snapshotValues(assembler.snapshot(start.Add(s.at))))
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssemblerShutdown(t *testing.T) {
+	const scope = "io.opentelemetry.runtime-telemetry-java17"
+	filter := testFilter{scopes: []string{scope}, metrics: []string{JVM_MEMORY_USED}}
+	tests := []struct {
+		name      string
+		add       bool
+		flushErr  error
+		wantErr   error
+		wantFlush int
+	}{
+		{name: "sends the last snapshot", add: true, wantFlush: 1},
+		{name: "nothing to send", add: false, wantFlush: 0},
+		{name: "flush error", add: true, flushErr: errors.New("backend unavailable"), wantErr: errors.New("backend unavailable"), wantFlush: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			flushed := 0
+			flush := func(context.Context, pmetric.Metrics) error {
+				mu.Lock()
+				defer mu.Unlock()
+				flushed++
+				return tt.flushErr
+			}
+			assembler := NewAssembler(AssemblyConfig{Interval: time.Hour, MaxStaleness: time.Hour}, filter, flush, zap.NewNop())
+			assembler.Start()
+			if tt.add {
+				assembler.Add(newAssemblerBatch([]assemblerPoint{{instance: "a", scope: scope, name: JVM_MEMORY_USED, series: "eden", value: 1}}), time.Now())
+			}
+			assert.Equal(t, tt.wantErr, assembler.Shutdown(context.Background()))
+			// 再次调用不会重复关闭或重复发送
+			require.NoError(t, assembler.Shutdown(context.Background()))
+			assert.Equal(t, tt.wantFlush, flushed)
+		})
+	}
+}
+
+func TestAssemblyConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AssemblyConfig
+		wantErr string
+	}{
+		{name: "default", cfg: NewDefaultAssemblyConfig()},
+		{name: "enabled", cfg: AssemblyConfig{Interval: time.Minute, MaxStaleness: 5 * time.Minute}},
+		{name: "negative interval", cfg: AssemblyConfig{Interval: -time.Second}, wantErr: "interval must not be negative"},
+		{name: "staleness shorter than interval", cfg: AssemblyConfig{Interval: time.Minute, MaxStaleness: time.Second}, wantErr: "max_staleness must not be shorter than interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
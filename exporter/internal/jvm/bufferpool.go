@@ -0,0 +1,58 @@
+package jvm
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	JVM_BUFFER_MEMORY_USED  = "jvm.buffer.memory.used"
+	JVM_BUFFER_MEMORY_LIMIT = "jvm.buffer.memory.limit"
+	JVM_BUFFER_COUNT        = "jvm.buffer.count"
+
+	JVM_BUFFER_POOL_NAME = "jvm.buffer.pool.name"
+
+	bufferPoolDirect = "direct"
+	// JDK 14 以后还有 "mapped - 'non-volatile memory'"，统一按前缀归入 mapped
+	bufferPoolMapped = "mapped"
+)
+
+// bufferPoolUsage 对应内部格式中单个缓冲池的数据
+type bufferPoolUsage struct {
+	count    int64
+	used     int64
+	capacity int64
+}
+
+// bufferPools 汇总一个批次中 direct 和 mapped 缓冲池的数据
+type bufferPools struct {
+	direct bufferPoolUsage
+	mapped bufferPoolUsage
+}
+
+// add 按 jvm.buffer.pool.name 将数据点累加到对应的缓冲池，无法识别的池会被忽略
+func (p *bufferPools) add(metricName string, dataPoint pmetric.NumberDataPoint) {
+	name, ok := dataPoint.Attributes().Get(JVM_BUFFER_POOL_NAME)
+	if !ok {
+		return
+	}
+	var usage *bufferPoolUsage
+	switch poolName := name.AsString(); {
+	case poolName == bufferPoolDirect:
+		usage = &p.direct
+	case strings.HasPrefix(poolName, bufferPoolMapped):
+		usage = &p.mapped
+	default:
+		return
+	}
+	value := numberDataPointInt(dataPoint)
+	switch metricName {
+	case JVM_BUFFER_MEMORY_USED:
+		usage.used += value
+	case JVM_BUFFER_MEMORY_LIMIT:
+		usage.capacity += value
+	case JVM_BUFFER_COUNT:
+		usage.count += value
+	}
+}
@@ -0,0 +1,84 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestBufferPoolsAdd(t *testing.T) {
+	// point 是一个 jvm.buffer.* 数据点，pool 为空时不带 jvm.buffer.pool.name
+	type point struct {
+		metric string
+		pool   string
+		value  int64
+	}
+	tests := []struct {
+		name   string
+		points []point
+		want   bufferPools
+	}{
+		{
+			name: "direct and mapped",
+			points: []point{
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "direct", value: 100},
+				{metric: JVM_BUFFER_MEMORY_LIMIT, pool: "direct", value: 200},
+				{metric: JVM_BUFFER_COUNT, pool: "direct", value: 3},
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "mapped", value: 10},
+				{metric: JVM_BUFFER_MEMORY_LIMIT, pool: "mapped", value: 20},
+				{metric: JVM_BUFFER_COUNT, pool: "mapped", value: 1},
+			},
+			want: bufferPools{
+				direct: bufferPoolUsage{count: 3, used: 100, capacity: 200},
+				mapped: bufferPoolUsage{count: 1, used: 10, capacity: 20},
+			},
+		},
+		{
+			name: "non-volatile mapped pool is added to mapped",
+			points: []point{
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "mapped", value: 10},
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "mapped - 'non-volatile memory'", value: 5},
+			},
+			want: bufferPools{mapped: bufferPoolUsage{used: 15}},
+		},
+		{
+			name: "unknown pools are ignored",
+			points: []point{
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "direct", value: 100},
+				{metric: JVM_BUFFER_MEMORY_USED, pool: "custom", value: 50},
+				{metric: JVM_BUFFER_MEMORY_USED, value: 25},
+			},
+			want: bufferPools{direct: bufferPoolUsage{used: 100}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pools bufferPools
+			for _, p := range tt.points {
+				dataPoint := pmetric.NewNumberDataPoint()
+				dataPoint.SetIntValue(p.value)
+				if p.pool != "" {
+					dataPoint.Attributes().PutStr(JVM_BUFFER_POOL_NAME, p.pool)
+				}
+				pools.add(p.metric, dataPoint)
+			}
+			assert.Equal(t, tt.want, pools)
+		})
+	}
+}
+
+func TestConverterTransformBufferPools(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := appendJVMMetrics(md, map[string]any{SERVICE_INSTANCE_ID: "a"})
+	newSumMetric(JVM_BUFFER_MEMORY_USED, "By", 100, map[string]any{JVM_BUFFER_POOL_NAME: "direct"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(JVM_BUFFER_MEMORY_LIMIT, "By", 200, map[string]any{JVM_BUFFER_POOL_NAME: "direct"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(JVM_BUFFER_COUNT, "{buffer}", 2, map[string]any{JVM_BUFFER_POOL_NAME: "mapped"}).CopyTo(metrics.AppendEmpty())
+
+	snapshots := newTestConverter(t, newTestConverterConfig()).Transform(md)
+	require.Len(t, snapshots, 1)
+	bufferPool := snapshots[0].Message.BufferPool
+	assert.Equal(t, BufferPoolInfo{Used: 100, Capacity: 200}, bufferPool.Direct)
+	assert.Equal(t, BufferPoolInfo{Count: 2}, bufferPool.Mapped)
+}
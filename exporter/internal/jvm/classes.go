@@ -0,0 +1,93 @@
+package jvm
+
+const (
+	JVM_CLASS_LOADED   = "jvm.class.loaded"
+	JVM_CLASS_UNLOADED = "jvm.class.unloaded"
+	JVM_CLASS_COUNT    = "jvm.class.count"
+	// JIT 编译耗时不在 JVM 语义约定中，OpenTelemetry Java agent 也不上报。数据来自
+	// Micrometer 的 JvmCompilationMetrics：OTLP 上报为 jvm.compilation.time，Prometheus
+	// 抓取为 jvm_compilation_time_ms_total，单位均为毫秒，由 micrometer 和 prometheus
+	// 方言换算为秒后以本名称进入转换逻辑
+	JVM_COMPILATION_TIME = "jvm.compilation.time"
+
+	// Micrometer 的 JIT 编译器名称属性
+	JVM_COMPILER_NAME = "compiler"
+)
+
+// counterObservation 是一个批次中某个计数器的观测值
+type counterObservation struct {
+	value   float64
+	isDelta bool
+	ok      bool
+}
+
+func (o *counterObservation) set(value float64, isDelta bool) {
+	if isDelta && o.ok {
+		o.value += value
+	} else {
+		o.value = value
+	}
+	o.isDelta = isDelta
+	o.ok = true
+}
+
+// cumulativeCounter 将 delta 或 cumulative 计数统一为自进程启动以来的累计值
+type cumulativeCounter struct {
+	total float64
+}
+
+func (c *cumulativeCounter) update(obs counterObservation) float64 {
+	if obs.ok {
+		if obs.isDelta {
+			c.total += obs.value
+		} else {
+			c.total = obs.value
+		}
+	}
+	return c.total
+}
+
+// classObservation 是一个批次中观测到的类加载和 JIT 编译指标
+type classObservation struct {
+	count    int64
+	hasCount bool
+	loaded   counterObservation
+	unloaded counterObservation
+
+	compiler        string
+	compilationTime counterObservation
+}
+
+func (o *classObservation) hasClassLoading() bool {
+	return o.hasCount || o.loaded.ok || o.unloaded.ok
+}
+
+// classState 保存单个 JVM 的类加载和 JIT 编译累计值，进程重启时随 jvmState 一起重置
+type classState struct {
+	loaded          cumulativeCounter
+	unloaded        cumulativeCounter
+	compilationTime cumulativeCounter
+}
+
+// classValues 对应内部格式 ClassLoading 和 Compilation 部分的字段
+type classValues struct {
+	loadedClassCount      int64
+	totalLoadedClassCount int64
+	unloadedClassCount    int64
+	// 毫秒
+	totalCompilationTime int64
+}
+
+func (s *classState) update(obs classObservation) classValues {
+	values := classValues{
+		loadedClassCount:      obs.count,
+		totalLoadedClassCount: int64(s.loaded.update(obs.loaded)),
+		unloadedClassCount:    int64(s.unloaded.update(obs.unloaded)),
+		totalCompilationTime:  int64(s.compilationTime.update(obs.compilationTime) * 1000),
+	}
+	// 没有 jvm.class.count 时用累计加载数减去卸载数推算
+	if !obs.hasCount && obs.loaded.ok {
+		values.loadedClassCount = values.totalLoadedClassCount - values.unloadedClassCount
+	}
+	return values
+}
@@ -0,0 +1,86 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestClassStateUpdate(t *testing.T) {
+	counter := func(value float64, isDelta bool) counterObservation {
+		return counterObservation{value: value, isDelta: isDelta, ok: true}
+	}
+	tests := []struct {
+		name  string
+		steps []classObservation
+		want  []classValues
+	}{
+		{
+			name: "cumulative counters",
+			steps: []classObservation{
+				{count: 900, hasCount: true, loaded: counter(1000, false), unloaded: counter(100, false)},
+				{count: 950, hasCount: true, loaded: counter(1100, false), unloaded: counter(150, false)},
+			},
+			want: []classValues{
+				{loadedClassCount: 900, totalLoadedClassCount: 1000, unloadedClassCount: 100},
+				{loadedClassCount: 950, totalLoadedClassCount: 1100, unloadedClassCount: 150},
+			},
+		},
+		{
+			name: "delta counters are accumulated",
+			steps: []classObservation{
+				{loaded: counter(1000, true), unloaded: counter(100, true)},
+				{loaded: counter(100, true)},
+			},
+			want: []classValues{
+				{loadedClassCount: 900, totalLoadedClassCount: 1000, unloadedClassCount: 100},
+				{loadedClassCount: 1000, totalLoadedClassCount: 1100, unloadedClassCount: 100},
+			},
+		},
+		{
+			name: "compilation time in milliseconds",
+			steps: []classObservation{
+				{compiler: "HotSpot 64-Bit Tiered Compilers", compilationTime: counter(1.5, false)},
+				{compilationTime: counter(0.25, true)},
+			},
+			want: []classValues{
+				{totalCompilationTime: 1500},
+				{totalCompilationTime: 1750},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &classState{}
+			for i, obs := range tt.steps {
+				assert.Equal(t, tt.want[i], state.update(obs), "step %d", i)
+			}
+		})
+	}
+}
+
+func TestConverterTransformClasses(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := appendJVMMetrics(md, map[string]any{SERVICE_INSTANCE_ID: "a"})
+	newSumMetric(JVM_CLASS_COUNT, "{class}", 900, nil).CopyTo(metrics.AppendEmpty())
+	newSumMetric(JVM_CLASS_LOADED, "{class}", 1000, nil).CopyTo(metrics.AppendEmpty())
+	newSumMetric(JVM_CLASS_UNLOADED, "{class}", 100, nil).CopyTo(metrics.AppendEmpty())
+	// Micrometer 的 JvmCompilationMetrics 以毫秒上报
+	compilation := metrics.AppendEmpty()
+	compilation.SetName("jvm.compilation.time")
+	compilation.SetUnit("ms")
+	sum := compilation.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(true)
+	dataPoint := sum.DataPoints().AppendEmpty()
+	dataPoint.Attributes().PutStr("compiler", "HotSpot 64-Bit Tiered Compilers")
+	dataPoint.SetDoubleValue(2345)
+
+	snapshots := newTestConverter(t, newTestConverterConfig()).Transform(md)
+	require.Len(t, snapshots, 1)
+	message := snapshots[0].Message
+	assert.Equal(t, ClassLoading{LoadedClassCount: 900, TotalLoadedClassCount: 1000, UnloadedClassCount: 100}, message.ClassLoading)
+	assert.Equal(t, Compilation{Name: "HotSpot 64-Bit Tiered Compilers", TotalCompilationTime: 2345}, message.Compilation)
+}
@@ -0,0 +1,625 @@
+// Package jvm converts OTLP JVM metrics into the JVM payload shared by jvmhttpexporter and
+// jvmxexporter, and holds the state both exporters keep between batches.
+package jvm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	JVM_MEMORY_USED      = "jvm.memory.used"
+	JVM_MEMORY_COMMITTED = "jvm.memory.committed"
+	JVM_MEMORY_LIMITI    = "jvm.memory.limit"
+	JVM_GC_DURATION      = "jvm.gc.duration"
+	JVM_THREAD_COUNT     = "jvm.thread.count"
+
+	JVM_CPU_RECENT_UTILIZATION = "jvm.cpu.recent_utilization"
+	JVM_SYSTEM_CPU_UTILIZATION = "jvm.system.cpu.utilization"
+	JVM_CPU_TIME               = "jvm.cpu.time"
+	JVM_CPU_COUNT              = "jvm.cpu.count"
+
+	JVM_GC_NAME          = "jvm.gc.name"
+	JVM_THREAD_DAEMON    = "jvm.thread.daemon"
+	JVM_MEMORY_POOL_NAME = "jvm.memory.pool.name"
+
+	SERVICE_NAME        = "service.name"
+	SERVICE_INSTANCE_ID = "service.instance.id"
+	HOST_NAME           = "host.name"
+	PROCESS_PID         = "process.pid"
+)
+
+// ConverterConfig holds the settings of the metric conversion shared by the JVM exporters.
+type ConverterConfig struct {
+	Identity IdentityConfig
+	// MasterIP is the template for masterIp. Empty leaves masterIp empty.
+	MasterIP        string
+	NameMapping     NameMappingConfig
+	CPU             CPUConfig
+	Input           InputConfig
+	LeakDetection   LeakDetectionConfig
+	TimestampFormat string
+	Health          HealthConfig
+}
+
+// Validate checks the conversion settings. Errors are prefixed with the configuration key of the
+// invalid section.
+func (cfg *ConverterConfig) Validate() error {
+	if _, err := NewIdentity(cfg.Identity); err != nil {
+		return fmt.Errorf("identity: %w", err)
+	}
+	if _, err := ParseTemplate(cfg.MasterIP); err != nil {
+		return fmt.Errorf("identity: invalid master_ip template: %w", err)
+	}
+	if err := cfg.NameMapping.Validate(); err != nil {
+		return fmt.Errorf("name_mapping: %w", err)
+	}
+	if err := cfg.CPU.Validate(); err != nil {
+		return fmt.Errorf("cpu: %w", err)
+	}
+	if err := cfg.Input.Validate(); err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+	if err := cfg.LeakDetection.Validate(); err != nil {
+		return fmt.Errorf("leak_detection: %w", err)
+	}
+	if err := ValidateTimestampFormat(cfg.TimestampFormat); err != nil {
+		return fmt.Errorf("timestamp_format: %w", err)
+	}
+	if err := cfg.Health.Validate(); err != nil {
+		return fmt.Errorf("health: %w", err)
+	}
+	return nil
+}
+
+// Converter 负责将 OTLP 指标转换为内部格式，并保存各 JVM 跨批次的状态
+type Converter struct {
+	identity      *Identity
+	masterIP      *Template
+	names         *nameMapper
+	normalizer    *metricNormalizer
+	cpuWindow     time.Duration
+	leakDetection LeakDetectionConfig
+	timeFormat    string
+	health        HealthConfig
+	threadDumps   *ThreadDumpStore
+	logger        *zap.Logger
+	// offline 不为空时记录各 JVM 的最后上报时间
+	offline *OfflineTracker
+
+	// mu 保护 states
+	mu     sync.Mutex
+	states *jvmStates
+}
+
+// NewConverter creates a converter. Thread details are read from threadDumps, which may be nil.
+func NewConverter(cfg ConverterConfig, threadDumps *ThreadDumpStore, set component.TelemetrySettings) (*Converter, error) {
+	id, err := NewIdentity(cfg.Identity)
+	if err != nil {
+		return nil, err
+	}
+	masterIP, err := ParseTemplate(cfg.MasterIP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master_ip template: %w", err)
+	}
+	telemetry, err := newExporterTelemetry(set)
+	if err != nil {
+		return nil, err
+	}
+	names, err := newNameMapper(cfg.NameMapping, set.Logger, telemetry)
+	if err != nil {
+		return nil, err
+	}
+	normalizer, err := newMetricNormalizer(cfg.Input, set.Logger, telemetry)
+	if err != nil {
+		return nil, err
+	}
+	if threadDumps == nil {
+		threadDumps = NewThreadDumpStore()
+	}
+	return &Converter{
+		identity:      id,
+		masterIP:      masterIP,
+		names:         names,
+		normalizer:    normalizer,
+		cpuWindow:     cfg.CPU.AverageWindow,
+		leakDetection: cfg.LeakDetection,
+		timeFormat:    cfg.TimestampFormat,
+		health:        cfg.Health,
+		threadDumps:   threadDumps,
+		logger:        set.Logger,
+		states:        newJVMStates(),
+	}, nil
+}
+
+// TrackOffline records the last report of every converted JVM in tracker.
+func (c *Converter) TrackOffline(tracker *OfflineTracker) {
+	c.offline = tracker
+}
+
+// MatchScope reports whether metrics of the instrumentation scope are converted.
+func (c *Converter) MatchScope(name string) bool {
+	return c.normalizer.matchScope(name)
+}
+
+// MatchMetric reports whether the metric belongs to one of the enabled dialects. Metrics of an
+// unexpected type are accepted and skipped during conversion.
+func (c *Converter) MatchMetric(metric pmetric.Metric) bool {
+	return c.normalizer.accepts(metric)
+}
+
+// Snapshot is the converted message of one JVM.
+type Snapshot struct {
+	// Key identifies the JVM across batches.
+	Key     string
+	Message *JManagementMessage
+	// ThreadDetails are the thread details reported through logs, also rendered into
+	// Message.Thread.ThreadInfos.
+	ThreadDetails []ThreadDetail
+	MasterIP      string
+}
+
+// jvmConversion 是单个 JVM 在当前批次中的转换上下文
+type jvmConversion struct {
+	message  *JManagementMessage
+	masterIP string
+	state    *jvmState
+	cpu      cpuObservation
+	buffers  bufferPools
+	threads  threadObservation
+	classes  classObservation
+	db       dbObservation
+	gcPoints []gcPoint
+	runtime  runtimeObservation
+	health   healthObservation
+	// 日志中上报的线程详情
+	threadDetails []ThreadDetail
+	// 批次中各累计序列的起始时间，用于识别进程重启
+	seriesStarts map[string]seriesStart
+}
+
+// Transform 将 OTLP 指标按 JVM 实例拆分并转换为内部格式，每个 JVM 一份快照；
+// 批次中没有 JVM 指标时返回空
+func (c *Converter) Transform(md pmetric.Metrics) []Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.states.expire(now)
+
+	conversions := make(map[string]*jvmConversion)
+	// 保持资源在批次中出现的顺序
+	var keys []string
+	resourceMetrics := md.ResourceMetrics()
+	rmsLen := resourceMetrics.Len()
+	for i := 0; i < rmsLen; i++ {
+		resourceMetric := resourceMetrics.At(i)
+		resource := resourceMetric.Resource()
+		resourceAttributes := resource.Attributes()
+		key := ResourceKey(resourceAttributes)
+		conversion, exists := conversions[key]
+		if !exists {
+			conversion = &jvmConversion{
+				message:      &JManagementMessage{},
+				seriesStarts: make(map[string]seriesStart),
+			}
+		}
+		converted := false
+		scopeMetrics := resourceMetric.ScopeMetrics()
+		smsLen := scopeMetrics.Len()
+		for i := 0; i < smsLen; i++ {
+			scopeMetric := scopeMetrics.At(i)
+			scopeName := scopeMetric.Scope().Name()
+			if c.normalizer.matchScope(scopeName) {
+				metrics := scopeMetric.Metrics()
+				msLen := metrics.Len()
+				for i := 0; i < msLen; i++ {
+					// 将旧版 agent、Micrometer 等来源的指标统一为语义约定形式，其他指标跳过
+					metric, ok := c.normalizer.normalize(metrics.At(i))
+					if !ok {
+						continue
+					}
+					addSeriesStarts(conversion.seriesStarts, metric)
+					conversion.runtime.addMetric(metric)
+					c.copeMetric(conversion, metric)
+					converted = true
+				}
+			}
+		}
+		if !converted {
+			continue
+		}
+		jManagementMessage := conversion.message
+		if appname, b := resourceAttributes.Get(SERVICE_NAME); b {
+			jManagementMessage.AppName = appname.AsString()
+		}
+		if pid, b := resourceAttributes.Get(PROCESS_PID); b {
+			jManagementMessage.Pid = pid.AsString()
+		}
+		conversion.runtime.addResource(resourceAttributes)
+		jManagementMessage.AgentId, jManagementMessage.MultiAgentId = c.identity.AgentIDs(resourceAttributes, key)
+		conversion.masterIP = c.masterIP.Render(resourceAttributes)
+		if !exists {
+			conversions[key] = conversion
+			keys = append(keys, key)
+		}
+	}
+
+	snapshots := make([]Snapshot, 0, len(keys))
+	for _, key := range keys {
+		conversion := conversions[key]
+		conversion.state = c.states.get(key, now)
+		conversion.threadDetails = c.threadDumps.take(key, now)
+		c.finish(conversion, now)
+		if c.offline != nil {
+			c.offline.seen(OfflineAgent{
+				Key:          key,
+				AgentID:      conversion.message.AgentId,
+				MultiAgentID: conversion.message.MultiAgentId,
+				AppName:      conversion.message.AppName,
+				Pid:          conversion.message.Pid,
+				Version:      conversion.message.Version,
+				Docker:       conversion.message.Docker,
+				MasterIP:     conversion.masterIP,
+				LastSeen:     now,
+			})
+		}
+		snapshots = append(snapshots, Snapshot{
+			Key:           key,
+			Message:       conversion.message,
+			ThreadDetails: conversion.threadDetails,
+			MasterIP:      conversion.masterIP,
+		})
+	}
+	return snapshots
+}
+
+// Offline 为离线的 JVM 生成最后一条消息并清理其状态，JVM 重新上报时从头计算
+func (c *Converter) Offline(agents []OfflineAgent, now time.Time) []Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(agents))
+	for _, agent := range agents {
+		delete(c.states.states, agent.Key)
+		snapshots = append(snapshots, Snapshot{
+			Key: agent.Key,
+			Message: &JManagementMessage{
+				AgentId:       agent.AgentID,
+				MultiAgentId:  agent.MultiAgentID,
+				AppName:       agent.AppName,
+				Pid:           agent.Pid,
+				Version:       agent.Version,
+				Docker:        agent.Docker,
+				CreationTime:  FormatTimestamp(now, c.timeFormat),
+				Status:        HealthStatusOffline,
+				StatusReasons: []string{offlineStatusReason},
+			},
+			MasterIP: agent.MasterIP,
+		})
+	}
+	return snapshots
+}
+
+// finish 结合跨批次状态计算需要历史数据的字段
+func (c *Converter) finish(conversion *jvmConversion, now time.Time) {
+	if conversion.state.checkRestart(conversion.message.Pid, conversion.seriesStarts) {
+		c.logger.Info("JVM restart detected, resetting state", zap.String("agentId", conversion.message.AgentId))
+	}
+	if appStartTime, ok := conversion.runtime.appStartTime(conversion.seriesStarts); ok {
+		conversion.message.AppStartTime = FormatTimestamp(appStartTime, c.timeFormat)
+	}
+	conversion.message.CreationTime = FormatTimestamp(conversion.runtime.creationTime(now), c.timeFormat)
+	conversion.message.Version = conversion.runtime.version
+	conversion.message.Docker = conversion.runtime.docker
+	// 健康规则的输入，没有数据的规则不参与计算
+	healthValues := make(map[string]float64)
+	if heap, ok := conversion.health.heapUtilization(); ok {
+		healthValues[healthRuleHeapUtilization] = heap
+	}
+	if oldGen, ok := conversion.health.oldGenAfterGC(); ok {
+		healthValues[healthRuleOldGenAfterGC] = oldGen
+	}
+	if len(conversion.gcPoints) > 0 {
+		totals := conversion.state.gc.update(conversion.gcPoints)
+		var gcTime float64
+		for _, total := range totals {
+			gcTime += total.sum
+		}
+		if ratio, ok := conversion.state.health.gcTimeRatio(gcTime, conversion.runtime.creationTime(now)); ok {
+			healthValues[healthRuleGCTimeRatio] = ratio
+		}
+		for name, total := range totals {
+			conversion.message.GarbageCollector.GarbageCollectors[name] = &GarbageCollectorInfo{
+				Name:            name,
+				CollectionCount: total.count,
+				CollectionTime:  int(total.sum * 1000),
+			}
+		}
+	}
+	if conversion.classes.hasClassLoading() || conversion.classes.compilationTime.ok {
+		values := conversion.state.classes.update(conversion.classes)
+		if conversion.classes.hasClassLoading() {
+			conversion.message.ClassLoading = ClassLoading{
+				LoadedClassCount:      values.loadedClassCount,
+				TotalLoadedClassCount: values.totalLoadedClassCount,
+				UnloadedClassCount:    values.unloadedClassCount,
+			}
+		}
+		if conversion.classes.compilationTime.ok {
+			conversion.message.Compilation = Compilation{
+				Name:                 conversion.classes.compiler,
+				TotalCompilationTime: values.totalCompilationTime,
+			}
+		}
+	}
+	if !conversion.db.empty() {
+		connections, suspicions := conversion.state.db.update(conversion.db, c.leakDetection)
+		databaseConnections := &conversion.message.DatabaseConnectionMessage
+		for _, connection := range connections {
+			databaseConnections.DatabaseConnectionMessageArray = append(databaseConnections.DatabaseConnectionMessageArray, DatabaseConnection{
+				PoolName:        connection.poolName,
+				Idle:            connection.idle,
+				Used:            connection.used,
+				Max:             connection.max,
+				PendingRequests: connection.pendingRequests,
+				WaitTime:        connection.waitTime,
+				UseTime:         connection.useTime,
+			})
+		}
+		for _, suspicion := range suspicions {
+			databaseConnections.LeakSuspicious = append(databaseConnections.LeakSuspicious, LeakSuspicion{
+				PoolName:  suspicion.poolName,
+				Reason:    suspicion.reason,
+				Intervals: suspicion.intervals,
+				Used:      suspicion.used,
+				Max:       suspicion.max,
+				WaitTime:  suspicion.waitTime,
+			})
+		}
+	}
+	if !conversion.threads.empty() {
+		thread := &conversion.message.Thread
+		thread.ThreadCount = conversion.threads.count
+		thread.DeamonThreadCount = conversion.threads.daemonCount
+		thread.StateCounts = conversion.threads.states
+		thread.PeakThreadCount, thread.TotalStartedThreadCount = conversion.state.threads.update(conversion.threads)
+		if conversion.threads.hasCount {
+			healthValues[healthRuleThreadCount] = float64(conversion.threads.count)
+		}
+	}
+	if len(conversion.threadDetails) > 0 {
+		threadInfos := &conversion.message.Thread.ThreadInfos
+		threadInfos.LockNames = lockNames(conversion.threadDetails)
+		for _, detail := range conversion.threadDetails {
+			threadInfos.ThreadInfo = append(threadInfos.ThreadInfo, []interface{}{
+				detail.id, detail.name, detail.state, detail.lockName,
+				detail.lockOwnerID, detail.lockOwnerName, detail.deadlocked, detail.stackTrace,
+			})
+		}
+	}
+	buffers := conversion.buffers
+	conversion.message.BufferPool.Direct = BufferPoolInfo{
+		Count:    buffers.direct.count,
+		Used:     buffers.direct.used,
+		Capacity: buffers.direct.capacity,
+	}
+	conversion.message.BufferPool.Mapped = BufferPoolInfo{
+		Count:    buffers.mapped.count,
+		Used:     buffers.mapped.used,
+		Capacity: buffers.mapped.capacity,
+	}
+	if !conversion.cpu.empty() {
+		values := conversion.state.cpu.update(conversion.cpu, c.cpuWindow, now)
+		conversion.message.CPU = CPU{
+			ProcessCpu:    values.processCPU,
+			SystemCpu:     values.systemCPU,
+			AvgProcessCpu: values.avgProcessCPU,
+			AvgSystemCpu:  values.avgSystemCPU,
+		}
+		if conversion.cpu.hasProcessCPU || conversion.cpu.hasCPUTime {
+			healthValues[healthRuleProcessCPU] = values.avgProcessCPU
+		}
+	}
+	conversion.message.Status, conversion.message.StatusReasons = c.health.evaluate(healthValues)
+}
+
+// ResourceKey 计算 JVM 实例的标识：优先使用 service.instance.id，否则组合 host.name 与 process.pid
+func ResourceKey(attributes pcommon.Map) string {
+	if v, ok := attributes.Get(SERVICE_INSTANCE_ID); ok && v.AsString() != "" {
+		return v.AsString()
+	}
+	parts := make([]string, 0, 3)
+	for _, name := range []string{SERVICE_NAME, HOST_NAME, PROCESS_PID} {
+		if v, ok := attributes.Get(name); ok {
+			parts = append(parts, v.AsString())
+		} else {
+			parts = append(parts, "")
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (c *Converter) copeMetric(conversion *jvmConversion, metric pmetric.Metric) {
+	jManagementMessage := conversion.message
+	// 确保 MemoryPool 和 GarbageCollector 的 maps 被初始化
+	if jManagementMessage.MemoryPool.MemoryUsages == nil {
+		jManagementMessage.MemoryPool.MemoryUsages = make(map[string]*MemoryUsage)
+	}
+	if jManagementMessage.GarbageCollector.GarbageCollectors == nil {
+		jManagementMessage.GarbageCollector.GarbageCollectors = make(map[string]*GarbageCollectorInfo)
+	}
+
+	switch metric.Name() {
+	case JVM_MEMORY_USED, JVM_MEMORY_COMMITTED, JVM_MEMORY_LIMITI:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			conversion.health.addMemory(metric.Name(), dataPoint)
+			dataPointAttributes := dataPoint.Attributes()
+			v, b := dataPointAttributes.Get(JVM_MEMORY_POOL_NAME)
+			if b {
+				poolName := v.AsString()
+				// 获取池名映射
+				mappedName := c.names.memoryPool(poolName)
+				// 确保内存池数据被初始化
+				if _, exists := jManagementMessage.MemoryPool.MemoryUsages[mappedName]; !exists {
+					jManagementMessage.MemoryPool.MemoryUsages[mappedName] = &MemoryUsage{
+						Max: -1,
+					}
+				}
+				// 根据字段填充数据
+				memoryUsage := jManagementMessage.MemoryPool.MemoryUsages[mappedName]
+				if metric.Name() == JVM_MEMORY_USED {
+					memoryUsage.Used = numberDataPointInt(dataPoint)
+				} else if metric.Name() == JVM_MEMORY_COMMITTED {
+					memoryUsage.Committed = numberDataPointInt(dataPoint)
+				} else if metric.Name() == JVM_MEMORY_LIMITI {
+					memoryUsage.Max = numberDataPointInt(dataPoint)
+				}
+			}
+		}
+	case JVM_MEMORY_USED_AFTER_LAST_GC:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.health.addMemory(metric.Name(), dataPoints.At(i))
+		}
+	case JVM_GC_DURATION:
+		histogram := metric.Histogram()
+		dataPoints := histogram.DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			dataPointAttributes := dataPoint.Attributes()
+			name, b := dataPointAttributes.Get(JVM_GC_NAME)
+			if b {
+				// 同一个垃圾收集器可能按 jvm.gc.action/jvm.gc.cause 拆分为多个序列，在 finish 中合并
+				garbageCollectorName := c.names.garbageCollector(name.AsString())
+				conversion.gcPoints = append(conversion.gcPoints, newGCPoint(garbageCollectorName, histogram, dataPoint))
+			}
+		}
+	case JVM_THREAD_COUNT:
+		dataPoints := numberDataPoints(metric)
+		var threadCount int64
+		var daemonThreadCount int64
+		var hasDaemon bool
+		var states map[string]int64
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			currentThreadCount := numberDataPointInt(dataPoint)
+			threadCount += currentThreadCount
+			if state, b := dataPoint.Attributes().Get(JVM_THREAD_STATE); b {
+				if states == nil {
+					states = make(map[string]int64)
+				}
+				states[threadStateName(state.AsString())] += currentThreadCount
+			}
+			isDaemon, b := dataPoint.Attributes().Get(JVM_THREAD_DAEMON)
+			if b {
+				hasDaemon = true
+				if isDaemon.Bool() {
+					daemonThreadCount += currentThreadCount
+				}
+			}
+		}
+		// 峰值需要结合历史状态，在 finish 中计算
+		conversion.threads.count = threadCount
+		conversion.threads.hasCount = true
+		// 没有 jvm.thread.daemon 属性时守护线程数由 jvm.threads.daemon 提供
+		if hasDaemon {
+			conversion.threads.daemonCount = daemonThreadCount
+		}
+		// jvm.threads.live 等不带状态的指标不覆盖已有的状态统计
+		if states != nil {
+			conversion.threads.states = states
+		}
+	case JVM_THREADS_DAEMON:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.threads.daemonCount = numberDataPointInt(dataPoints.At(i))
+		}
+	case JVM_THREADS_PEAK:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			if peak := numberDataPointInt(dataPoints.At(i)); peak > conversion.threads.peak {
+				conversion.threads.peak = peak
+			}
+			conversion.threads.hasPeak = true
+		}
+	case JVM_THREADS_STARTED:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.threads.setStarted(isDeltaMetric(metric), dataPoints.At(i))
+		}
+	case JVM_CPU_RECENT_UTILIZATION, JVM_SYSTEM_CPU_UTILIZATION:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			conversion.cpu.observe(dataPoint.Timestamp())
+			if metric.Name() == JVM_CPU_RECENT_UTILIZATION {
+				conversion.cpu.processCPU = numberDataPointDouble(dataPoint)
+				conversion.cpu.hasProcessCPU = true
+			} else {
+				conversion.cpu.systemCPU = numberDataPointDouble(dataPoint)
+				conversion.cpu.hasSystemCPU = true
+			}
+		}
+	case JVM_CPU_TIME:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			conversion.cpu.observe(dataPoint.Timestamp())
+			conversion.cpu.setCPUTime(isDeltaMetric(metric), dataPoint)
+		}
+	case JVM_CPU_COUNT:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.cpu.cpuCount = numberDataPointInt(dataPoints.At(i))
+		}
+	case JVM_BUFFER_MEMORY_USED, JVM_BUFFER_MEMORY_LIMIT, JVM_BUFFER_COUNT:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.buffers.add(metric.Name(), dataPoints.At(i))
+		}
+	case JVM_CLASS_COUNT:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.classes.count = numberDataPointInt(dataPoints.At(i))
+			conversion.classes.hasCount = true
+		}
+	case JVM_CLASS_LOADED, JVM_CLASS_UNLOADED:
+		counter := &conversion.classes.loaded
+		if metric.Name() == JVM_CLASS_UNLOADED {
+			counter = &conversion.classes.unloaded
+		}
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			counter.set(numberDataPointDouble(dataPoints.At(i)), isDeltaMetric(metric))
+		}
+	case JVM_COMPILATION_TIME:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			dataPoint := dataPoints.At(i)
+			if compiler, ok := dataPoint.Attributes().Get(JVM_COMPILER_NAME); ok {
+				conversion.classes.compiler = compiler.AsString()
+			}
+			conversion.classes.compilationTime.set(numberDataPointDouble(dataPoint), isDeltaMetric(metric))
+		}
+	case DB_CLIENT_CONNECTIONS_USAGE, DB_CLIENT_CONNECTIONS_MAX, DB_CLIENT_CONNECTIONS_PENDING_REQUESTS:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.db.addNumber(metric.Name(), dataPoints.At(i))
+		}
+	case DB_CLIENT_CONNECTIONS_WAIT_TIME, DB_CLIENT_CONNECTIONS_USE_TIME:
+		dataPoints := metric.Histogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			conversion.db.addHistogram(metric, dataPoints.At(i))
+		}
+	}
+}
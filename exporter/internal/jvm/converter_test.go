@@ -0,0 +1,293 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const testScope = "io.opentelemetry.runtime-telemetry-java17"
+
+func newTestConverterConfig() ConverterConfig {
+	return ConverterConfig{
+		Identity:        NewDefaultIdentityConfig(),
+		NameMapping:     NewDefaultNameMappingConfig(),
+		CPU:             NewDefaultCPUConfig(),
+		Input:           NewDefaultInputConfig(),
+		LeakDetection:   NewDefaultLeakDetectionConfig(),
+		TimestampFormat: DefaultTimestampFormat,
+		Health:          NewDefaultHealthConfig(),
+	}
+}
+
+func newTestConverter(t *testing.T, cfg ConverterConfig) *Converter {
+	converter, err := NewConverter(cfg, nil, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	return converter
+}
+
+// appendJVMMetrics 添加一个资源并返回其 JVM scope 下的指标列表
+func appendJVMMetrics(md pmetric.Metrics, attributes map[string]any) pmetric.MetricSlice {
+	resourceMetric := md.ResourceMetrics().AppendEmpty()
+	_ = resourceMetric.Resource().Attributes().FromRaw(attributes)
+	scopeMetric := resourceMetric.ScopeMetrics().AppendEmpty()
+	scopeMetric.Scope().SetName(testScope)
+	return scopeMetric.Metrics()
+}
+
+// newSumMetric 返回只有一个整数数据点的累计 Sum
+func newSumMetric(name string, unit string, value int64, attributes map[string]any) pmetric.Metric {
+	metric := pmetric.NewMetric()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	sum := metric.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dataPoint := sum.DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(value)
+	_ = dataPoint.Attributes().FromRaw(attributes)
+	return metric
+}
+
+// newGaugeMetric 返回只有一个浮点数据点的 Gauge
+func newGaugeMetric(name string, unit string, value float64, attributes map[string]any) pmetric.Metric {
+	metric := pmetric.NewMetric()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetDoubleValue(value)
+	_ = dataPoint.Attributes().FromRaw(attributes)
+	return metric
+}
+
+// memoryUsed 返回快照中各内存池的使用量
+func memoryUsed(snapshot Snapshot) map[string]int64 {
+	used := make(map[string]int64)
+	for name, usage := range snapshot.Message.MemoryPool.MemoryUsages {
+		used[name] = usage.Used
+	}
+	return used
+}
+
+func TestConverterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*ConverterConfig)
+		wantErr string
+	}{
+		{name: "default", modify: func(*ConverterConfig) {}},
+		{
+			name:    "unclosed agent_id placeholder",
+			modify:  func(cfg *ConverterConfig) { cfg.Identity.AgentID = "${service.name" },
+			wantErr: `identity: invalid agent_id template: unclosed placeholder in "${service.name"`,
+		},
+		{
+			name:    "empty multi_agent_id attribute",
+			modify:  func(cfg *ConverterConfig) { cfg.Identity.MultiAgentID = "${service.name|}" },
+			wantErr: "identity: invalid multi_agent_id template: empty attribute name in placeholder",
+		},
+		{
+			name:    "unclosed master_ip placeholder",
+			modify:  func(cfg *ConverterConfig) { cfg.MasterIP = "${host.ip" },
+			wantErr: `identity: invalid master_ip template: unclosed placeholder in "${host.ip"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConverterConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConverterTransform(t *testing.T) {
+	// resource 是批次中的一个资源及其 G1 Eden Space 使用量
+	type resource struct {
+		attributes map[string]any
+		scope      string
+		used       int64
+	}
+	tests := []struct {
+		name      string
+		resources []resource
+		wantKeys  []string
+		wantUsed  []int64
+	}{
+		{
+			name: "one payload per resource",
+			resources: []resource{
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "a"}, used: 1},
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "b"}, used: 2},
+			},
+			wantKeys: []string{"a", "b"},
+			wantUsed: []int64{1, 2},
+		},
+		{
+			name: "pods of one service are not merged",
+			resources: []resource{
+				{attributes: map[string]any{SERVICE_NAME: "bookdemo", HOST_NAME: "pod-a", PROCESS_PID: int64(1)}, used: 1},
+				{attributes: map[string]any{SERVICE_NAME: "bookdemo", HOST_NAME: "pod-b", PROCESS_PID: int64(1)}, used: 2},
+				{attributes: map[string]any{SERVICE_NAME: "bookdemo", HOST_NAME: "pod-c", PROCESS_PID: int64(1)}, used: 3},
+			},
+			wantKeys: []string{"bookdemo/pod-a/1", "bookdemo/pod-b/1", "bookdemo/pod-c/1"},
+			wantUsed: []int64{1, 2, 3},
+		},
+		{
+			name: "resources of one JVM are merged",
+			resources: []resource{
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "a"}, used: 1},
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "b"}, used: 2},
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "a"}, used: 3},
+			},
+			wantKeys: []string{"a", "b"},
+			wantUsed: []int64{3, 2},
+		},
+		{
+			name: "resources without JVM metrics are skipped",
+			resources: []resource{
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "a"}, scope: "io.opentelemetry.http", used: 1},
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "b"}, used: 2},
+			},
+			wantKeys: []string{"b"},
+			wantUsed: []int64{2},
+		},
+		{
+			name: "no JVM metrics",
+			resources: []resource{
+				{attributes: map[string]any{SERVICE_INSTANCE_ID: "a"}, scope: "io.opentelemetry.http", used: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := pmetric.NewMetrics()
+			for _, r := range tt.resources {
+				resourceMetric := md.ResourceMetrics().AppendEmpty()
+				_ = resourceMetric.Resource().Attributes().FromRaw(r.attributes)
+				scopeMetric := resourceMetric.ScopeMetrics().AppendEmpty()
+				scopeMetric.Scope().SetName(testScope)
+				if r.scope != "" {
+					scopeMetric.Scope().SetName(r.scope)
+				}
+				newSumMetric(JVM_MEMORY_USED, "By", r.used, map[string]any{JVM_MEMORY_POOL_NAME: "G1 Eden Space"}).CopyTo(scopeMetric.Metrics().AppendEmpty())
+			}
+
+			snapshots := newTestConverter(t, newTestConverterConfig()).Transform(md)
+			var keys []string
+			var used []int64
+			for _, snapshot := range snapshots {
+				keys = append(keys, snapshot.Key)
+				used = append(used, memoryUsed(snapshot)["G1EdenSpace"])
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.Equal(t, tt.wantUsed, used)
+		})
+	}
+}
+
+func TestConverterTransformIdentity(t *testing.T) {
+	tests := []struct {
+		name             string
+		modify           func(*ConverterConfig)
+		attributes       map[string]any
+		wantAgentID      string
+		wantMultiAgentID string
+		wantMasterIP     string
+	}{
+		{
+			name: "default templates",
+			attributes: map[string]any{
+				SERVICE_NAME: "bookdemo", "k8s.pod.name": "bookdemo-7d9f", SERVICE_INSTANCE_ID: "0b5c",
+				"host.ip": "192.168.136.105", "server.port": int64(8080),
+			},
+			wantAgentID:      "bookdemo-bookdemo-7d9f-0b5c@192.168.136.105:8080",
+			wantMultiAgentID: "bookdemo-bookdemo-7d9f-0b5c@192.168.136.105:8080",
+		},
+		{
+			name:             "defaults for missing attributes",
+			attributes:       map[string]any{HOST_NAME: "node-1", PROCESS_PID: int64(4242)},
+			wantAgentID:      "unknown-node-1-4242@127.0.0.1:0",
+			wantMultiAgentID: "unknown-node-1-4242@127.0.0.1:0",
+		},
+		{
+			name: "resource identity when the template renders empty",
+			modify: func(cfg *ConverterConfig) {
+				cfg.Identity.AgentID = "${agent.id}"
+				cfg.Identity.MultiAgentID = "${service.name}"
+			},
+			attributes:       map[string]any{SERVICE_NAME: "bookdemo", HOST_NAME: "node-1", PROCESS_PID: int64(4242)},
+			wantAgentID:      "bookdemo/node-1/4242",
+			wantMultiAgentID: "bookdemo",
+		},
+		{
+			name:             "master ip",
+			modify:           func(cfg *ConverterConfig) { cfg.MasterIP = "${host.ip|k8s.pod.ip:-127.0.0.1}" },
+			attributes:       map[string]any{SERVICE_INSTANCE_ID: "a", "k8s.pod.ip": "10.0.0.7"},
+			wantAgentID:      "unknown-unknown-a@10.0.0.7:0",
+			wantMultiAgentID: "unknown-unknown-a@10.0.0.7:0",
+			wantMasterIP:     "10.0.0.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConverterConfig()
+			if tt.modify != nil {
+				tt.modify(&cfg)
+			}
+			md := pmetric.NewMetrics()
+			newSumMetric(JVM_MEMORY_USED, "By", 1, map[string]any{JVM_MEMORY_POOL_NAME: "G1 Eden Space"}).CopyTo(appendJVMMetrics(md, tt.attributes).AppendEmpty())
+
+			snapshots := newTestConverter(t, cfg).Transform(md)
+			require.Len(t, snapshots, 1)
+			assert.Equal(t, tt.wantAgentID, snapshots[0].Message.AgentId)
+			assert.Equal(t, tt.wantMultiAgentID, snapshots[0].Message.MultiAgentId)
+			assert.Equal(t, tt.wantMasterIP, snapshots[0].MasterIP)
+		})
+	}
+}
+
+func TestResourceKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		want       string
+	}{
+		{
+			name:       "service.instance.id",
+			attributes: map[string]any{SERVICE_INSTANCE_ID: "0b5c", SERVICE_NAME: "bookdemo", HOST_NAME: "node-1", PROCESS_PID: int64(4242)},
+			want:       "0b5c",
+		},
+		{
+			name:       "host and pid",
+			attributes: map[string]any{SERVICE_NAME: "bookdemo", HOST_NAME: "node-1", PROCESS_PID: int64(4242)},
+			want:       "bookdemo/node-1/4242",
+		},
+		{
+			name:       "empty service.instance.id",
+			attributes: map[string]any{SERVICE_INSTANCE_ID: "", HOST_NAME: "node-1", PROCESS_PID: int64(4242)},
+			want:       "/node-1/4242",
+		},
+		{
+			name:       "service.name only",
+			attributes: map[string]any{SERVICE_NAME: "bookdemo"},
+			want:       "bookdemo//",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := pcommon.NewMap()
+			require.NoError(t, attributes.FromRaw(tt.attributes))
+			assert.Equal(t, tt.want, ResourceKey(attributes))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package jvm
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const defaultCPUAverageWindow = 5 * time.Minute
+
+// CPUConfig defines how the CPU section of the JVM payload is computed.
+type CPUConfig struct {
+	// AverageWindow is the time window over which avgProcessCpu and avgSystemCpu are averaged.
+	AverageWindow time.Duration `mapstructure:"average_window"`
+}
+
+func NewDefaultCPUConfig() CPUConfig {
+	return CPUConfig{AverageWindow: defaultCPUAverageWindow}
+}
+
+// Validate checks the CPU configuration.
+func (cfg *CPUConfig) Validate() error {
+	if cfg.AverageWindow <= 0 {
+		return errors.New("average_window must be positive")
+	}
+	return nil
+}
+
+// cpuObservation 是一个批次中观测到的 CPU 指标
+type cpuObservation struct {
+	processCPU    float64
+	hasProcessCPU bool
+	systemCPU     float64
+	hasSystemCPU  bool
+
+	// jvm.cpu.time，单位秒
+	cpuTime        float64
+	cpuTimeStart   pcommon.Timestamp
+	cpuTimeAt      pcommon.Timestamp
+	cpuTimeIsDelta bool
+	hasCPUTime     bool
+
+	cpuCount int64
+
+	timestamp pcommon.Timestamp
+}
+
+func (o *cpuObservation) empty() bool {
+	return !o.hasProcessCPU && !o.hasSystemCPU && !o.hasCPUTime
+}
+
+// observe 记录数据点时间，取批次中最新的时间作为采样时间
+func (o *cpuObservation) observe(timestamp pcommon.Timestamp) {
+	if timestamp > o.timestamp {
+		o.timestamp = timestamp
+	}
+}
+
+func (o *cpuObservation) setCPUTime(isDelta bool, dataPoint pmetric.NumberDataPoint) {
+	// 多个数据点时只保留最新的一个
+	if o.hasCPUTime && dataPoint.Timestamp() < o.cpuTimeAt {
+		return
+	}
+	o.cpuTime = numberDataPointDouble(dataPoint)
+	o.cpuTimeStart = dataPoint.StartTimestamp()
+	o.cpuTimeAt = dataPoint.Timestamp()
+	o.cpuTimeIsDelta = isDelta
+	o.hasCPUTime = true
+}
+
+type cpuSample struct {
+	timestamp     time.Time
+	processCPU    float64
+	hasProcessCPU bool
+	systemCPU     float64
+	hasSystemCPU  bool
+}
+
+// cpuState 保存单个 JVM 计算 CPU 使用率所需的历史数据
+type cpuState struct {
+	samples  []cpuSample
+	cpuCount int64
+
+	lastCPUTime   float64
+	lastCPUTimeAt pcommon.Timestamp
+}
+
+// cpuValues 对应内部格式 CPU 部分的四个字段
+type cpuValues struct {
+	processCPU    float64
+	systemCPU     float64
+	avgProcessCPU float64
+	avgSystemCPU  float64
+}
+
+// update 合并本批次的观测值，返回当前值和窗口内的平均值
+func (s *cpuState) update(obs cpuObservation, window time.Duration, now time.Time) cpuValues {
+	if obs.cpuCount > 0 {
+		s.cpuCount = obs.cpuCount
+	}
+
+	timestamp := now
+	if obs.timestamp != 0 {
+		timestamp = obs.timestamp.AsTime()
+	}
+	sample := cpuSample{
+		timestamp:     timestamp,
+		processCPU:    obs.processCPU,
+		hasProcessCPU: obs.hasProcessCPU,
+		systemCPU:     obs.systemCPU,
+		hasSystemCPU:  obs.hasSystemCPU,
+	}
+	if obs.hasCPUTime {
+		// 没有 jvm.cpu.recent_utilization 时根据 jvm.cpu.time 推算进程 CPU 使用率
+		if utilization, ok := s.cpuTimeUtilization(obs); ok && !sample.hasProcessCPU {
+			sample.processCPU = utilization
+			sample.hasProcessCPU = true
+		}
+	}
+
+	s.samples = append(s.samples, sample)
+	cutoff := sample.timestamp.Add(-window)
+	kept := s.samples[:0]
+	for _, sm := range s.samples {
+		if sm.timestamp.After(cutoff) {
+			kept = append(kept, sm)
+		}
+	}
+	s.samples = kept
+
+	values := cpuValues{
+		processCPU: sample.processCPU,
+		systemCPU:  sample.systemCPU,
+	}
+	var processSum, systemSum float64
+	var processCount, systemCount int
+	for _, sm := range s.samples {
+		if sm.hasProcessCPU {
+			processSum += sm.processCPU
+			processCount++
+		}
+		if sm.hasSystemCPU {
+			systemSum += sm.systemCPU
+			systemCount++
+		}
+	}
+	if processCount > 0 {
+		values.avgProcessCPU = processSum / float64(processCount)
+	}
+	if systemCount > 0 {
+		values.avgSystemCPU = systemSum / float64(systemCount)
+	}
+	return values
+}
+
+func (s *cpuState) cpuTimeUtilization(obs cpuObservation) (float64, bool) {
+	var used float64
+	var elapsed time.Duration
+	if obs.cpuTimeIsDelta {
+		used = obs.cpuTime
+		elapsed = obs.cpuTimeAt.AsTime().Sub(obs.cpuTimeStart.AsTime())
+	} else {
+		if s.lastCPUTimeAt != 0 && obs.cpuTimeAt <= s.lastCPUTimeAt {
+			// 重复或乱序的数据点
+			return 0, false
+		}
+		if s.lastCPUTimeAt != 0 && obs.cpuTime >= s.lastCPUTime {
+			used = obs.cpuTime - s.lastCPUTime
+			elapsed = obs.cpuTimeAt.AsTime().Sub(s.lastCPUTimeAt.AsTime())
+		}
+		s.lastCPUTime = obs.cpuTime
+		s.lastCPUTimeAt = obs.cpuTimeAt
+	}
+	if elapsed <= 0 || s.cpuCount <= 0 {
+		return 0, false
+	}
+	return used / elapsed.Seconds() / float64(s.cpuCount), true
+}
@@ -0,0 +1,184 @@
+package jvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestCPUConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CPUConfig
+		wantErr string
+	}{
+		{name: "default", cfg: NewDefaultCPUConfig()},
+		{name: "zero window", cfg: CPUConfig{}, wantErr: "average_window must be positive"},
+		{name: "negative window", cfg: CPUConfig{AverageWindow: -time.Minute}, wantErr: "average_window must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCPUStateUpdate(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) pcommon.Timestamp { return pcommon.NewTimestampFromTime(start.Add(d)) }
+	// process 和 system 为 recent_utilization 和 system.cpu.utilization，负数表示没有上报
+	utilization := func(d time.Duration, process, system float64) cpuObservation {
+		obs := cpuObservation{timestamp: at(d)}
+		if process >= 0 {
+			obs.processCPU, obs.hasProcessCPU = process, true
+		}
+		if system >= 0 {
+			obs.systemCPU, obs.hasSystemCPU = system, true
+		}
+		return obs
+	}
+	// cpuTime 为 jvm.cpu.time 数据点，delta 时从 from 累计到 d
+	cpuTime := func(from, d time.Duration, seconds float64, delta bool, count int64) cpuObservation {
+		return cpuObservation{
+			cpuTime:        seconds,
+			cpuTimeStart:   at(from),
+			cpuTimeAt:      at(d),
+			cpuTimeIsDelta: delta,
+			hasCPUTime:     true,
+			cpuCount:       count,
+			timestamp:      at(d),
+		}
+	}
+	tests := []struct {
+		name  string
+		steps []cpuObservation
+		want  []cpuValues
+	}{
+		{
+			name: "average over the window",
+			steps: []cpuObservation{
+				utilization(0, 0.2, 0.5),
+				utilization(2*time.Minute, 0.4, 0.7),
+				utilization(4*time.Minute, 0.6, 0.9),
+				// 窗口为 5 分钟，第一个样本被移出窗口
+				utilization(6*time.Minute, 0.8, 0.5),
+			},
+			want: []cpuValues{
+				{processCPU: 0.2, systemCPU: 0.5, avgProcessCPU: 0.2, avgSystemCPU: 0.5},
+				{processCPU: 0.4, systemCPU: 0.7, avgProcessCPU: 0.3, avgSystemCPU: 0.6},
+				{processCPU: 0.6, systemCPU: 0.9, avgProcessCPU: 0.4, avgSystemCPU: 0.7},
+				{processCPU: 0.8, systemCPU: 0.5, avgProcessCPU: 0.6, avgSystemCPU: 0.7},
+			},
+		},
+		{
+			name: "missing values are not averaged",
+			steps: []cpuObservation{
+				utilization(0, 0.2, 0.5),
+				utilization(time.Minute, -1, 0.7),
+				utilization(2*time.Minute, 0.4, -1),
+			},
+			want: []cpuValues{
+				{processCPU: 0.2, systemCPU: 0.5, avgProcessCPU: 0.2, avgSystemCPU: 0.5},
+				{systemCPU: 0.7, avgProcessCPU: 0.2, avgSystemCPU: 0.6},
+				{processCPU: 0.4, avgProcessCPU: 0.3, avgSystemCPU: 0.6},
+			},
+		},
+		{
+			name: "cumulative cpu time",
+			steps: []cpuObservation{
+				cpuTime(0, 0, 10, false, 2),
+				cpuTime(0, 30*time.Second, 40, false, 0),
+				cpuTime(0, time.Minute, 46, false, 0),
+			},
+			want: []cpuValues{
+				{},
+				{processCPU: 0.5, avgProcessCPU: 0.5},
+				{processCPU: 0.1, avgProcessCPU: 0.3},
+			},
+		},
+		{
+			name: "duplicate cumulative cpu time",
+			steps: []cpuObservation{
+				cpuTime(0, 0, 10, false, 1),
+				cpuTime(0, 30*time.Second, 25, false, 0),
+				cpuTime(0, 30*time.Second, 25, false, 0),
+			},
+			want: []cpuValues{
+				{},
+				{processCPU: 0.5, avgProcessCPU: 0.5},
+				{avgProcessCPU: 0.5},
+			},
+		},
+		{
+			name: "delta cpu time",
+			steps: []cpuObservation{
+				cpuTime(0, 30*time.Second, 15, true, 1),
+				cpuTime(30*time.Second, time.Minute, 3, true, 0),
+			},
+			want: []cpuValues{
+				{processCPU: 0.5, avgProcessCPU: 0.5},
+				{processCPU: 0.1, avgProcessCPU: 0.3},
+			},
+		},
+		{
+			name: "cpu time is divided by the cpu count",
+			steps: []cpuObservation{
+				cpuTime(0, 30*time.Second, 60, true, 4),
+				cpuTime(30*time.Second, time.Minute, 60, true, 8),
+			},
+			want: []cpuValues{
+				{processCPU: 0.5, avgProcessCPU: 0.5},
+				{processCPU: 0.25, avgProcessCPU: 0.375},
+			},
+		},
+		{
+			name: "cpu time without cpu count",
+			steps: []cpuObservation{
+				cpuTime(0, 30*time.Second, 15, true, 0),
+			},
+			want: []cpuValues{{}},
+		},
+		{
+			name: "recent utilization takes precedence over cpu time",
+			steps: []cpuObservation{
+				func() cpuObservation {
+					obs := cpuTime(0, 30*time.Second, 15, true, 1)
+					obs.processCPU, obs.hasProcessCPU = 0.2, true
+					return obs
+				}(),
+			},
+			want: []cpuValues{{processCPU: 0.2, avgProcessCPU: 0.2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &cpuState{}
+			for i, obs := range tt.steps {
+				got := state.update(obs, defaultCPUAverageWindow, start)
+				assert.InDelta(t, tt.want[i].processCPU, got.processCPU, 1e-9, "step %d processCpu", i)
+				assert.InDelta(t, tt.want[i].systemCPU, got.systemCPU, 1e-9, "step %d systemCpu", i)
+				assert.InDelta(t, tt.want[i].avgProcessCPU, got.avgProcessCPU, 1e-9, "step %d avgProcessCpu", i)
+				assert.InDelta(t, tt.want[i].avgSystemCPU, got.avgSystemCPU, 1e-9, "step %d avgSystemCpu", i)
+			}
+		})
+	}
+}
+
+func TestConverterTransformCPU(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := appendJVMMetrics(md, map[string]any{SERVICE_INSTANCE_ID: "a"})
+	newGaugeMetric(JVM_CPU_RECENT_UTILIZATION, "1", 0.25, nil).CopyTo(metrics.AppendEmpty())
+	newGaugeMetric(JVM_SYSTEM_CPU_UTILIZATION, "1", 0.5, nil).CopyTo(metrics.AppendEmpty())
+
+	snapshots := newTestConverter(t, newTestConverterConfig()).Transform(md)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, CPU{ProcessCpu: 0.25, SystemCpu: 0.5, AvgProcessCpu: 0.25, AvgSystemCpu: 0.5}, snapshots[0].Message.CPU)
+}
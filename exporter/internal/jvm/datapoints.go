@@ -0,0 +1,79 @@
+package jvm
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// numberDataPoints 返回 Gauge 或 Sum 的数据点，其他类型返回空集合
+func numberDataPoints(metric pmetric.Metric) pmetric.NumberDataPointSlice {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return metric.Gauge().DataPoints()
+	case pmetric.MetricTypeSum:
+		return metric.Sum().DataPoints()
+	}
+	return pmetric.NewNumberDataPointSlice()
+}
+
+// isDeltaMetric 判断指标是否为 delta 类型，Gauge 等没有聚合周期的指标返回 false
+func isDeltaMetric(metric pmetric.Metric) bool {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		return metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+	}
+	return false
+}
+
+// numberDataPointInt 读取整数值，double 类型的数据点取整
+func numberDataPointInt(dataPoint pmetric.NumberDataPoint) int64 {
+	if dataPoint.ValueType() == pmetric.NumberDataPointValueTypeDouble {
+		return int64(dataPoint.DoubleValue())
+	}
+	return dataPoint.IntValue()
+}
+
+// numberDataPointDouble 读取浮点值，整数类型的数据点转换为 float64
+func numberDataPointDouble(dataPoint pmetric.NumberDataPoint) float64 {
+	if dataPoint.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dataPoint.IntValue())
+	}
+	return dataPoint.DoubleValue()
+}
+
+// latestTimestamp 返回指标数据点中最新的时间，没有数据点时返回 0
+func latestTimestamp(metric pmetric.Metric) pcommon.Timestamp {
+	var latest pcommon.Timestamp
+	observe := func(ts pcommon.Timestamp) {
+		if ts > latest {
+			latest = ts
+		}
+	}
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeSum:
+		dataPoints := numberDataPoints(metric)
+		for i := 0; i < dataPoints.Len(); i++ {
+			observe(dataPoints.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		dataPoints := metric.Histogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			observe(dataPoints.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dataPoints := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			observe(dataPoints.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeSummary:
+		dataPoints := metric.Summary().DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			observe(dataPoints.At(i).Timestamp())
+		}
+	}
+	return latest
+}
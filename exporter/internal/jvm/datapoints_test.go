@@ -0,0 +1,117 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestNumberDataPointValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        func(pmetric.NumberDataPoint)
+		wantInt    int64
+		wantDouble float64
+	}{
+		{
+			name:       "int",
+			set:        func(dataPoint pmetric.NumberDataPoint) { dataPoint.SetIntValue(42) },
+			wantInt:    42,
+			wantDouble: 42,
+		},
+		{
+			name:       "double is truncated",
+			set:        func(dataPoint pmetric.NumberDataPoint) { dataPoint.SetDoubleValue(42.9) },
+			wantInt:    42,
+			wantDouble: 42.9,
+		},
+		{
+			name:       "empty",
+			set:        func(pmetric.NumberDataPoint) {},
+			wantInt:    0,
+			wantDouble: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPoint := pmetric.NewNumberDataPoint()
+			tt.set(dataPoint)
+			assert.Equal(t, tt.wantInt, numberDataPointInt(dataPoint))
+			assert.Equal(t, tt.wantDouble, numberDataPointDouble(dataPoint))
+		})
+	}
+}
+
+func TestNumberDataPointsAndTemporality(t *testing.T) {
+	tests := []struct {
+		name      string
+		metric    func() pmetric.Metric
+		wantLen   int
+		wantDelta bool
+		wantLast  pcommon.Timestamp
+	}{
+		{
+			name: "gauge",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				dataPoints := metric.SetEmptyGauge().DataPoints()
+				dataPoints.AppendEmpty().SetTimestamp(20)
+				dataPoints.AppendEmpty().SetTimestamp(10)
+				return metric
+			},
+			wantLen:  2,
+			wantLast: 20,
+		},
+		{
+			name: "delta sum",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				sum := metric.SetEmptySum()
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				sum.DataPoints().AppendEmpty().SetTimestamp(30)
+				return metric
+			},
+			wantLen:   1,
+			wantDelta: true,
+			wantLast:  30,
+		},
+		{
+			name: "delta histogram has no number data points",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				histogram := metric.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				histogram.DataPoints().AppendEmpty().SetTimestamp(40)
+				return metric
+			},
+			wantLen:   0,
+			wantDelta: true,
+			wantLast:  40,
+		},
+		{
+			name: "summary",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetEmptySummary().DataPoints().AppendEmpty().SetTimestamp(50)
+				return metric
+			},
+			wantLen:  0,
+			wantLast: 50,
+		},
+		{
+			name:    "empty metric",
+			metric:  pmetric.NewMetric,
+			wantLen: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := tt.metric()
+			assert.Equal(t, tt.wantLen, numberDataPoints(metric).Len())
+			assert.Equal(t, tt.wantDelta, isDeltaMetric(metric))
+			assert.Equal(t, tt.wantLast, latestTimestamp(metric))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package jvm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	DB_CLIENT_CONNECTIONS_USAGE            = "db.client.connections.usage"
+	DB_CLIENT_CONNECTIONS_MAX              = "db.client.connections.max"
+	DB_CLIENT_CONNECTIONS_PENDING_REQUESTS = "db.client.connections.pending_requests"
+	DB_CLIENT_CONNECTIONS_WAIT_TIME        = "db.client.connections.wait_time"
+	DB_CLIENT_CONNECTIONS_USE_TIME         = "db.client.connections.use_time"
+
+	DB_POOL_NAME = "pool.name"
+	DB_STATE     = "state"
+
+	dbStateIdle = "idle"
+	dbStateUsed = "used"
+
+	defaultLeakDetectionIntervals  = 3
+	defaultLeakDetectionUsageRatio = 1.0
+)
+
+// LeakDetectionConfig defines when a connection pool is reported as a leak suspect.
+//
+// A pool is suspected when its used connections stay at or above UsageRatio of the pool
+// maximum while the average wait time keeps rising for Intervals consecutive reports.
+type LeakDetectionConfig struct {
+	// Enabled turns leak suspicion reporting on or off.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Intervals is the number of consecutive reports the condition must hold.
+	Intervals int `mapstructure:"intervals"`
+
+	// UsageRatio is the fraction of the pool maximum that counts as exhausted, in (0, 1].
+	UsageRatio float64 `mapstructure:"usage_ratio"`
+}
+
+func NewDefaultLeakDetectionConfig() LeakDetectionConfig {
+	return LeakDetectionConfig{
+		Enabled:    true,
+		Intervals:  defaultLeakDetectionIntervals,
+		UsageRatio: defaultLeakDetectionUsageRatio,
+	}
+}
+
+// Validate checks the leak detection configuration.
+func (cfg *LeakDetectionConfig) Validate() error {
+	if cfg.Intervals < 1 {
+		return errors.New("intervals must be at least 1")
+	}
+	if cfg.UsageRatio <= 0 || cfg.UsageRatio > 1 {
+		return errors.New("usage_ratio must be in (0, 1]")
+	}
+	return nil
+}
+
+// dbTimeObservation 是连接等待或使用时长直方图在一个批次中的值，sum 单位为毫秒
+type dbTimeObservation struct {
+	count   uint64
+	sum     float64
+	isDelta bool
+	ok      bool
+}
+
+func (o *dbTimeObservation) add(metric pmetric.Metric, dataPoint pmetric.HistogramDataPoint) {
+	o.count += dataPoint.Count()
+	o.sum += dataPoint.Sum() * secondsPerUnit(metric.Unit(), "ms") * 1000
+	o.isDelta = isDeltaMetric(metric)
+	o.ok = true
+}
+
+// dbPoolObservation 是单个连接池在一个批次中的观测值
+type dbPoolObservation struct {
+	idle     int64
+	used     int64
+	max      int64
+	pending  int64
+	waitTime dbTimeObservation
+	useTime  dbTimeObservation
+}
+
+// dbObservation 按 pool.name 汇总一个批次中的连接池指标
+type dbObservation struct {
+	pools map[string]*dbPoolObservation
+}
+
+// pool 返回 pool.name 对应的观测值，不存在时创建
+func (o *dbObservation) pool(attributes pcommon.Map) (*dbPoolObservation, bool) {
+	name, ok := attributes.Get(DB_POOL_NAME)
+	if !ok {
+		return nil, false
+	}
+	if o.pools == nil {
+		o.pools = make(map[string]*dbPoolObservation)
+	}
+	pool, ok := o.pools[name.AsString()]
+	if !ok {
+		pool = &dbPoolObservation{}
+		o.pools[name.AsString()] = pool
+	}
+	return pool, true
+}
+
+func (o *dbObservation) empty() bool {
+	return len(o.pools) == 0
+}
+
+// addNumber 处理连接数类指标
+func (o *dbObservation) addNumber(metricName string, dataPoint pmetric.NumberDataPoint) {
+	pool, ok := o.pool(dataPoint.Attributes())
+	if !ok {
+		return
+	}
+	value := numberDataPointInt(dataPoint)
+	switch metricName {
+	case DB_CLIENT_CONNECTIONS_USAGE:
+		state, _ := dataPoint.Attributes().Get(DB_STATE)
+		switch state.AsString() {
+		case dbStateIdle:
+			pool.idle = value
+		case dbStateUsed:
+			pool.used = value
+		}
+	case DB_CLIENT_CONNECTIONS_MAX:
+		pool.max = value
+	case DB_CLIENT_CONNECTIONS_PENDING_REQUESTS:
+		pool.pending = value
+	}
+}
+
+// addHistogram 处理连接等待和使用时长
+func (o *dbObservation) addHistogram(metric pmetric.Metric, dataPoint pmetric.HistogramDataPoint) {
+	pool, ok := o.pool(dataPoint.Attributes())
+	if !ok {
+		return
+	}
+	switch metric.Name() {
+	case DB_CLIENT_CONNECTIONS_WAIT_TIME:
+		pool.waitTime.add(metric, dataPoint)
+	case DB_CLIENT_CONNECTIONS_USE_TIME:
+		pool.useTime.add(metric, dataPoint)
+	}
+}
+
+// dbTimeState 保存 cumulative 直方图上次的值，用于计算上报周期内的平均时长
+type dbTimeState struct {
+	count uint64
+	sum   float64
+}
+
+// average 返回本周期的平均时长，单位为毫秒
+func (s *dbTimeState) average(obs dbTimeObservation) float64 {
+	if !obs.ok {
+		return 0
+	}
+	count, sum := obs.count, obs.sum
+	if !obs.isDelta {
+		// 计数变小说明计数器被重置，此时整个累计值都属于本周期
+		if count >= s.count {
+			count, sum = count-s.count, sum-s.sum
+		}
+		s.count, s.sum = obs.count, obs.sum
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// dbPoolState 保存单个连接池跨批次的状态
+type dbPoolState struct {
+	waitTime dbTimeState
+	useTime  dbTimeState
+
+	lastWaitTime float64
+	// 连续满足泄漏条件的周期数
+	streak int
+}
+
+// dbState 保存单个 JVM 各连接池的状态，进程重启时随 jvmState 一起重置
+type dbState struct {
+	pools map[string]*dbPoolState
+}
+
+// dbConnection 对应内部格式中的一个 DatabaseConnection
+type dbConnection struct {
+	poolName        string
+	idle            int64
+	used            int64
+	max             int64
+	pendingRequests int64
+	waitTime        float64
+	useTime         float64
+}
+
+// dbLeakSuspicion 对应内部格式中的一个 LeakSuspicion
+type dbLeakSuspicion struct {
+	poolName  string
+	reason    string
+	intervals int
+	used      int64
+	max       int64
+	waitTime  float64
+}
+
+// update 计算各连接池本周期的数据并判断是否疑似连接泄漏，结果按池名排序
+func (s *dbState) update(obs dbObservation, cfg LeakDetectionConfig) ([]dbConnection, []dbLeakSuspicion) {
+	if s.pools == nil {
+		s.pools = make(map[string]*dbPoolState)
+	}
+	names := make([]string, 0, len(obs.pools))
+	for name := range obs.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	connections := make([]dbConnection, 0, len(names))
+	var suspicions []dbLeakSuspicion
+	for _, name := range names {
+		pool := obs.pools[name]
+		state, ok := s.pools[name]
+		if !ok {
+			state = &dbPoolState{}
+			s.pools[name] = state
+		}
+		connection := dbConnection{
+			poolName:        name,
+			idle:            pool.idle,
+			used:            pool.used,
+			max:             pool.max,
+			pendingRequests: pool.pending,
+			waitTime:        state.waitTime.average(pool.waitTime),
+			useTime:         state.useTime.average(pool.useTime),
+		}
+		connections = append(connections, connection)
+
+		// 已用连接数达到上限且平均等待时间持续上升
+		exhausted := pool.max > 0 && float64(pool.used) >= cfg.UsageRatio*float64(pool.max)
+		if exhausted && pool.waitTime.ok && connection.waitTime > state.lastWaitTime {
+			state.streak++
+		} else {
+			state.streak = 0
+		}
+		state.lastWaitTime = connection.waitTime
+
+		if cfg.Enabled && state.streak >= cfg.Intervals {
+			suspicions = append(suspicions, dbLeakSuspicion{
+				poolName: name,
+				reason: fmt.Sprintf("%d of %d connections in use with wait time rising for %d intervals",
+					pool.used, pool.max, state.streak),
+				intervals: state.streak,
+				used:      pool.used,
+				max:       pool.max,
+				waitTime:  connection.waitTime,
+			})
+		}
+	}
+	return connections, suspicions
+}
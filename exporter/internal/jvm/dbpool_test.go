@@ -0,0 +1,207 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestLeakDetectionConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*LeakDetectionConfig)
+		wantErr string
+	}{
+		{name: "default", modify: func(*LeakDetectionConfig) {}},
+		{name: "disabled", modify: func(cfg *LeakDetectionConfig) { cfg.Enabled = false }},
+		{name: "no intervals", modify: func(cfg *LeakDetectionConfig) { cfg.Intervals = 0 }, wantErr: "intervals must be at least 1"},
+		{name: "zero usage ratio", modify: func(cfg *LeakDetectionConfig) { cfg.UsageRatio = 0 }, wantErr: "usage_ratio must be in (0, 1]"},
+		{name: "usage ratio above 1", modify: func(cfg *LeakDetectionConfig) { cfg.UsageRatio = 1.5 }, wantErr: "usage_ratio must be in (0, 1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultLeakDetectionConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDBTimeStateAverage(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []dbTimeObservation
+		want  []float64
+	}{
+		{
+			name: "cumulative",
+			steps: []dbTimeObservation{
+				{count: 2, sum: 20, ok: true},
+				{count: 6, sum: 100, ok: true},
+				{count: 6, sum: 100, ok: true},
+			},
+			want: []float64{10, 20, 0},
+		},
+		{
+			name: "cumulative reset",
+			steps: []dbTimeObservation{
+				{count: 10, sum: 100, ok: true},
+				{count: 2, sum: 50, ok: true},
+			},
+			want: []float64{10, 25},
+		},
+		{
+			name: "delta",
+			steps: []dbTimeObservation{
+				{count: 2, sum: 20, isDelta: true, ok: true},
+				{count: 4, sum: 20, isDelta: true, ok: true},
+			},
+			want: []float64{10, 5},
+		},
+		{
+			name:  "not reported",
+			steps: []dbTimeObservation{{}},
+			want:  []float64{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &dbTimeState{}
+			for i, obs := range tt.steps {
+				assert.InDelta(t, tt.want[i], state.average(obs), 1e-9, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestDBStateUpdateLeakDetection(t *testing.T) {
+	// pool 返回一个连接池的观测值，waitTime 为本周期的平均等待时长
+	pool := func(used, max int64, waitTime float64) *dbPoolObservation {
+		return &dbPoolObservation{
+			used:     used,
+			max:      max,
+			waitTime: dbTimeObservation{count: 1, sum: waitTime, isDelta: true, ok: true},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(*LeakDetectionConfig)
+		steps  []map[string]*dbPoolObservation
+		// 每一步疑似泄漏的连接池及其持续周期数
+		want []map[string]int
+	}{
+		{
+			name: "fires after the configured intervals",
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(10, 10, 1)},
+				{"orders": pool(10, 10, 2)},
+				{"orders": pool(10, 10, 3)},
+				{"orders": pool(10, 10, 4)},
+			},
+			want: []map[string]int{{}, {}, {"orders": 3}, {"orders": 4}},
+		},
+		{
+			name: "resets when the wait time stops rising",
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(10, 10, 1)},
+				{"orders": pool(10, 10, 2)},
+				{"orders": pool(10, 10, 3)},
+				{"orders": pool(10, 10, 3)},
+				{"orders": pool(10, 10, 4)},
+			},
+			want: []map[string]int{{}, {}, {"orders": 3}, {}, {}},
+		},
+		{
+			name: "resets when connections are released",
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(10, 10, 1)},
+				{"orders": pool(10, 10, 2)},
+				{"orders": pool(10, 10, 3)},
+				{"orders": pool(5, 10, 4)},
+				{"orders": pool(10, 10, 5)},
+			},
+			want: []map[string]int{{}, {}, {"orders": 3}, {}, {}},
+		},
+		{
+			name: "grouped per pool",
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(10, 10, 1), "users": pool(10, 10, 1)},
+				{"orders": pool(10, 10, 2), "users": pool(2, 10, 2)},
+				{"orders": pool(10, 10, 3), "users": pool(10, 10, 3)},
+				{"orders": pool(10, 10, 4), "users": pool(10, 10, 4)},
+				{"orders": pool(10, 10, 5), "users": pool(10, 10, 5)},
+			},
+			want: []map[string]int{{}, {}, {"orders": 3}, {"orders": 4}, {"orders": 5, "users": 3}},
+		},
+		{
+			name:   "usage ratio",
+			modify: func(cfg *LeakDetectionConfig) { cfg.Intervals, cfg.UsageRatio = 1, 0.8 },
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(8, 10, 1)},
+				{"orders": pool(7, 10, 2)},
+			},
+			want: []map[string]int{{"orders": 1}, {}},
+		},
+		{
+			name:   "disabled",
+			modify: func(cfg *LeakDetectionConfig) { cfg.Enabled, cfg.Intervals = false, 1 },
+			steps: []map[string]*dbPoolObservation{
+				{"orders": pool(10, 10, 1)},
+				{"orders": pool(10, 10, 2)},
+			},
+			want: []map[string]int{{}, {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultLeakDetectionConfig()
+			if tt.modify != nil {
+				tt.modify(&cfg)
+			}
+			state := &dbState{}
+			for i, pools := range tt.steps {
+				connections, suspicions := state.update(dbObservation{pools: pools}, cfg)
+				assert.Len(t, connections, len(pools), "step %d", i)
+				got := make(map[string]int)
+				for _, suspicion := range suspicions {
+					got[suspicion.poolName] = suspicion.intervals
+				}
+				assert.Equal(t, tt.want[i], got, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestConverterTransformDBPools(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := appendJVMMetrics(md, map[string]any{SERVICE_INSTANCE_ID: "a"})
+	newSumMetric(DB_CLIENT_CONNECTIONS_USAGE, "{connection}", 3, map[string]any{DB_POOL_NAME: "users", DB_STATE: "idle"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(DB_CLIENT_CONNECTIONS_USAGE, "{connection}", 7, map[string]any{DB_POOL_NAME: "users", DB_STATE: "used"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(DB_CLIENT_CONNECTIONS_MAX, "{connection}", 10, map[string]any{DB_POOL_NAME: "users"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(DB_CLIENT_CONNECTIONS_PENDING_REQUESTS, "{request}", 2, map[string]any{DB_POOL_NAME: "users"}).CopyTo(metrics.AppendEmpty())
+	newSumMetric(DB_CLIENT_CONNECTIONS_MAX, "{connection}", 20, map[string]any{DB_POOL_NAME: "orders"}).CopyTo(metrics.AppendEmpty())
+	waitTime := metrics.AppendEmpty()
+	waitTime.SetName(DB_CLIENT_CONNECTIONS_WAIT_TIME)
+	waitTime.SetUnit("ms")
+	histogram := waitTime.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dataPoint := histogram.DataPoints().AppendEmpty()
+	dataPoint.Attributes().PutStr(DB_POOL_NAME, "users")
+	dataPoint.SetCount(4)
+	dataPoint.SetSum(10)
+
+	snapshots := newTestConverter(t, newTestConverterConfig()).Transform(md)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, []DatabaseConnection{
+		{PoolName: "orders", Max: 20},
+		{PoolName: "users", Idle: 3, Used: 7, Max: 10, PendingRequests: 2, WaitTime: 2.5},
+	}, snapshots[0].Message.DatabaseConnectionMessage.DatabaseConnectionMessageArray)
+	assert.Empty(t, snapshots[0].Message.DatabaseConnectionMessage.LeakSuspicious)
+}
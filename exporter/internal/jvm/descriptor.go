@@ -0,0 +1,144 @@
+package jvm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	PROCESS_RUNTIME_VERSION = "process.runtime.version"
+	PROCESS_START_TIME      = "process.start_time"
+	PROCESS_CREATION_TIME   = "process.creation.time"
+	CONTAINER_ID            = "container.id"
+)
+
+const (
+	// TimestampFormatUnixMilli renders timestamps as milliseconds since the Unix epoch.
+	TimestampFormatUnixMilli = "unix_milli"
+	// TimestampFormatUnix renders timestamps as seconds since the Unix epoch.
+	TimestampFormatUnix = "unix"
+	// TimestampFormatRFC3339 renders timestamps as RFC 3339 strings with millisecond precision.
+	TimestampFormatRFC3339 = "rfc3339"
+
+	DefaultTimestampFormat = TimestampFormatUnixMilli
+
+	rfc3339Milli = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// ValidateTimestampFormat 校验时间格式，除预定义格式外也接受 Go 的时间布局字符串
+func ValidateTimestampFormat(format string) error {
+	switch format {
+	case TimestampFormatUnixMilli, TimestampFormatUnix, TimestampFormatRFC3339:
+		return nil
+	}
+	if !strings.Contains(format, "2006") {
+		return fmt.Errorf("invalid timestamp_format %q, expected %q, %q, %q or a Go time layout",
+			format, TimestampFormatUnixMilli, TimestampFormatUnix, TimestampFormatRFC3339)
+	}
+	return nil
+}
+
+func FormatTimestamp(t time.Time, format string) string {
+	switch format {
+	case TimestampFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case TimestampFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimestampFormatRFC3339:
+		return t.Format(rfc3339Milli)
+	}
+	return t.Format(format)
+}
+
+// parseTimeAttribute 解析时间类资源属性：字符串按 RFC 3339 解析，
+// 数值按量级识别为秒、毫秒、微秒或纳秒
+func parseTimeAttribute(value pcommon.Value) (time.Time, bool) {
+	switch value.Type() {
+	case pcommon.ValueTypeStr:
+		if t, err := time.Parse(time.RFC3339Nano, value.Str()); err == nil {
+			return t, true
+		}
+		if n, err := strconv.ParseInt(value.Str(), 10, 64); err == nil {
+			return unixTime(n), true
+		}
+	case pcommon.ValueTypeInt:
+		return unixTime(value.Int()), true
+	case pcommon.ValueTypeDouble:
+		return unixTime(int64(value.Double())), true
+	}
+	return time.Time{}, false
+}
+
+func unixTime(n int64) time.Time {
+	switch {
+	case n > 1e17:
+		return time.Unix(0, n)
+	case n > 1e14:
+		return time.UnixMicro(n)
+	case n > 1e11:
+		return time.UnixMilli(n)
+	}
+	return time.Unix(n, 0)
+}
+
+// runtimeObservation 收集批次中用于填充 AppStartTime、CreationTime、Version、Docker 的信息
+type runtimeObservation struct {
+	version   string
+	docker    bool
+	startTime time.Time
+	// 批次中最新的数据点时间
+	latest pcommon.Timestamp
+}
+
+func (o *runtimeObservation) addResource(attributes pcommon.Map) {
+	if version, ok := attributes.Get(PROCESS_RUNTIME_VERSION); ok && version.AsString() != "" {
+		o.version = version.AsString()
+	}
+	if containerID, ok := attributes.Get(CONTAINER_ID); ok && containerID.AsString() != "" {
+		o.docker = true
+	}
+	for _, name := range []string{PROCESS_START_TIME, PROCESS_CREATION_TIME} {
+		if value, ok := attributes.Get(name); ok {
+			if startTime, ok := parseTimeAttribute(value); ok {
+				o.startTime = startTime
+				break
+			}
+		}
+	}
+}
+
+func (o *runtimeObservation) addMetric(metric pmetric.Metric) {
+	if latest := latestTimestamp(metric); latest > o.latest {
+		o.latest = latest
+	}
+}
+
+// appStartTime 优先使用资源属性中的进程启动时间，否则取累计指标中最早的起始时间
+func (o *runtimeObservation) appStartTime(starts map[string]seriesStart) (time.Time, bool) {
+	if !o.startTime.IsZero() {
+		return o.startTime, true
+	}
+	var earliest pcommon.Timestamp
+	for _, series := range starts {
+		if earliest == 0 || series.start < earliest {
+			earliest = series.start
+		}
+	}
+	if earliest == 0 {
+		return time.Time{}, false
+	}
+	return earliest.AsTime(), true
+}
+
+// creationTime 返回批次中最新的数据点时间，没有时使用当前时间
+func (o *runtimeObservation) creationTime(now time.Time) time.Time {
+	if o.latest == 0 {
+		return now
+	}
+	return o.latest.AsTime()
+}
@@ -0,0 +1,167 @@
+package jvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestValidateTimestampFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: TimestampFormatUnixMilli},
+		{format: TimestampFormatUnix},
+		{format: TimestampFormatRFC3339},
+		{format: "2006-01-02 15:04:05"},
+		{format: "", wantErr: true},
+		{format: "yyyy-MM-dd HH:mm:ss", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := ValidateTimestampFormat(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	ts := time.Date(2025, 3, 10, 8, 30, 15, 123456789, time.UTC)
+	tests := []struct {
+		format string
+		time   time.Time
+		want   string
+	}{
+		{format: TimestampFormatUnixMilli, time: ts, want: "1741595415123"},
+		{format: TimestampFormatUnix, time: ts, want: "1741595415"},
+		{format: TimestampFormatRFC3339, time: ts, want: "2025-03-10T08:30:15.123Z"},
+		{format: TimestampFormatRFC3339, time: ts.In(time.FixedZone("CST", 8*3600)), want: "2025-03-10T16:30:15.123+08:00"},
+		{format: "2006-01-02 15:04:05", time: ts, want: "2025-03-10 08:30:15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatTimestamp(tt.time, tt.format))
+		})
+	}
+}
+
+func TestParseTimeAttribute(t *testing.T) {
+	want := time.Date(2025, 3, 10, 8, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  pcommon.Value
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "rfc3339", value: pcommon.NewValueStr("2025-03-10T16:30:15+08:00"), want: want, wantOK: true},
+		{name: "numeric string", value: pcommon.NewValueStr("1741595415000"), want: want, wantOK: true},
+		{name: "seconds", value: pcommon.NewValueInt(1741595415), want: want, wantOK: true},
+		{name: "milliseconds", value: pcommon.NewValueInt(1741595415000), want: want, wantOK: true},
+		{name: "microseconds", value: pcommon.NewValueInt(1741595415000000), want: want, wantOK: true},
+		{name: "nanoseconds", value: pcommon.NewValueInt(1741595415000000000), want: want, wantOK: true},
+		{name: "double", value: pcommon.NewValueDouble(1741595415000), want: want, wantOK: true},
+		{name: "invalid string", value: pcommon.NewValueStr("yesterday")},
+		{name: "bool", value: pcommon.NewValueBool(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTimeAttribute(tt.value)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.True(t, tt.want.Equal(got), "got %s", got)
+			}
+		})
+	}
+}
+
+func TestConverterTransformDescriptor(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	// newUsedMetric 返回从 start 开始、在 start+at 采集的 jvm.memory.used
+	newUsedMetric := func(at time.Duration) pmetric.Metric {
+		metric := newSumMetric(JVM_MEMORY_USED, "By", 1024, map[string]any{JVM_MEMORY_POOL_NAME: "G1 Eden Space"})
+		dataPoint := metric.Sum().DataPoints().At(0)
+		dataPoint.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		dataPoint.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(at)))
+		return metric
+	}
+	tests := []struct {
+		name       string
+		format     string
+		attributes map[string]any
+		want       JManagementMessage
+	}{
+		{
+			name:   "decimal pid",
+			format: TimestampFormatUnixMilli,
+			attributes: map[string]any{
+				SERVICE_NAME: "bookdemo", PROCESS_PID: int64(4242),
+				PROCESS_RUNTIME_VERSION: "17.0.9+9", CONTAINER_ID: "3f4e",
+			},
+			want: JManagementMessage{
+				AppName:      "bookdemo",
+				Pid:          "4242",
+				Version:      "17.0.9+9",
+				Docker:       true,
+				AppStartTime: "1741593600000",
+				CreationTime: "1741593660000",
+			},
+		},
+		{
+			name:   "process start time attribute",
+			format: TimestampFormatRFC3339,
+			attributes: map[string]any{
+				SERVICE_NAME: "bookdemo", PROCESS_PID: "4242",
+				PROCESS_START_TIME: "2025-03-10T07:59:30Z",
+			},
+			want: JManagementMessage{
+				AppName:      "bookdemo",
+				Pid:          "4242",
+				AppStartTime: "2025-03-10T07:59:30.000Z",
+				CreationTime: "2025-03-10T08:01:00.000Z",
+			},
+		},
+		{
+			name:   "process creation time attribute",
+			format: TimestampFormatUnix,
+			attributes: map[string]any{
+				SERVICE_NAME: "bookdemo", PROCESS_PID: int64(4242),
+				PROCESS_CREATION_TIME: int64(1741593570000),
+			},
+			want: JManagementMessage{
+				AppName:      "bookdemo",
+				Pid:          "4242",
+				AppStartTime: "1741593570",
+				CreationTime: "1741593660",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConverterConfig()
+			cfg.TimestampFormat = tt.format
+			md := pmetric.NewMetrics()
+			metrics := appendJVMMetrics(md, tt.attributes)
+			newUsedMetric(30 * time.Second).CopyTo(metrics.AppendEmpty())
+			newUsedMetric(time.Minute).CopyTo(metrics.AppendEmpty())
+
+			snapshots := newTestConverter(t, cfg).Transform(md)
+			require.Len(t, snapshots, 1)
+			message := snapshots[0].Message
+			assert.Equal(t, tt.want.AppName, message.AppName)
+			assert.Equal(t, tt.want.Pid, message.Pid)
+			assert.Equal(t, tt.want.Version, message.Version)
+			assert.Equal(t, tt.want.Docker, message.Docker)
+			assert.Equal(t, tt.want.AppStartTime, message.AppStartTime)
+			assert.Equal(t, tt.want.CreationTime, message.CreationTime)
+		})
+	}
+}
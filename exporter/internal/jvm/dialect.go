@@ -0,0 +1,472 @@
+package jvm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+const (
+	// DialectSemconv accepts the current JVM semantic convention names (jvm.memory.used, ...).
+	DialectSemconv = "semconv"
+	// DialectLegacy accepts the process.runtime.jvm.* names of OpenTelemetry Java agents before 2.0.
+	DialectLegacy = "legacy"
+	// DialectMicrometer accepts Micrometer names exported over OTLP (jvm.memory.used{area,id}, ...).
+	DialectMicrometer = "micrometer"
+	// DialectPrometheus accepts Micrometer names scraped from Prometheus (jvm_memory_used_bytes, ...).
+	DialectPrometheus = "prometheus"
+
+	// Micrometer 上报的守护线程数
+	JVM_THREADS_DAEMON = "jvm.threads.daemon"
+
+	JVM_GC_ACTION   = "jvm.gc.action"
+	JVM_GC_CAUSE    = "jvm.gc.cause"
+	JVM_MEMORY_TYPE = "jvm.memory.type"
+)
+
+// InputConfig defines which metrics are accepted as conversion input.
+type InputConfig struct {
+	// Scopes lists instrumentation scope names whose metrics are converted. An entry matches
+	// any scope name containing it, "*" matches every scope and "" matches metrics without
+	// a scope name (as sent by Micrometer's OTLP registry).
+	Scopes []string `mapstructure:"scopes"`
+
+	// Dialects lists the metric naming conventions that are translated. Valid values are
+	// "semconv", "legacy", "micrometer" and "prometheus". Defaults to all of them.
+	Dialects []string `mapstructure:"dialects"`
+}
+
+func NewDefaultInputConfig() InputConfig {
+	return InputConfig{
+		Scopes: []string{
+			"io.opentelemetry.runtime-telemetry-java",
+			"io.opentelemetry.runtime-metrics",
+			"io.opentelemetry.micrometer",
+			"prometheusreceiver",
+			"",
+			// 连接池埋点
+			"io.opentelemetry.hikaricp",
+			"io.opentelemetry.c3p0",
+			"io.opentelemetry.apache-dbcp",
+			"io.opentelemetry.tomcat-jdbc",
+			"io.opentelemetry.vibur-dbcp",
+			"io.opentelemetry.oracle-ucp",
+			"io.opentelemetry.alibaba-druid",
+		},
+		Dialects: []string{DialectSemconv, DialectLegacy, DialectMicrometer, DialectPrometheus},
+	}
+}
+
+// Validate checks that the configured dialects exist.
+func (cfg *InputConfig) Validate() error {
+	if len(cfg.Scopes) == 0 {
+		return errors.New("at least one scope must be specified")
+	}
+	for _, dialect := range cfg.Dialects {
+		if _, ok := dialectRules[dialect]; !ok && dialect != DialectSemconv {
+			return fmt.Errorf("unknown dialect %q", dialect)
+		}
+	}
+	return nil
+}
+
+// metricShape 是语义约定指标在转换逻辑中期望的类型
+type metricShape struct {
+	metricType pmetric.MetricType
+	// 数值类指标期望的值类型
+	valueType pmetric.NumberDataPointValueType
+}
+
+var (
+	shapeIntSum      = metricShape{pmetric.MetricTypeSum, pmetric.NumberDataPointValueTypeInt}
+	shapeDoubleSum   = metricShape{pmetric.MetricTypeSum, pmetric.NumberDataPointValueTypeDouble}
+	shapeIntGauge    = metricShape{pmetric.MetricTypeGauge, pmetric.NumberDataPointValueTypeInt}
+	shapeDoubleGauge = metricShape{pmetric.MetricTypeGauge, pmetric.NumberDataPointValueTypeDouble}
+	shapeHistogram   = metricShape{metricType: pmetric.MetricTypeHistogram}
+)
+
+// semconvShapes 列出转换逻辑支持的指标及其类型，方言规则的目标必须在其中
+var semconvShapes = map[string]metricShape{
+	JVM_MEMORY_USED:            shapeIntSum,
+	JVM_MEMORY_COMMITTED:       shapeIntSum,
+	JVM_MEMORY_LIMITI:          shapeIntSum,
+	JVM_GC_DURATION:            shapeHistogram,
+	JVM_THREAD_COUNT:           shapeIntSum,
+	JVM_THREADS_PEAK:           shapeIntGauge,
+	JVM_THREADS_DAEMON:         shapeIntGauge,
+	JVM_THREADS_STARTED:        shapeIntSum,
+	JVM_CPU_RECENT_UTILIZATION: shapeDoubleGauge,
+	JVM_SYSTEM_CPU_UTILIZATION: shapeDoubleGauge,
+	JVM_CPU_TIME:               shapeDoubleSum,
+	JVM_CPU_COUNT:              shapeIntSum,
+	JVM_BUFFER_MEMORY_USED:     shapeIntSum,
+	JVM_BUFFER_MEMORY_LIMIT:    shapeIntSum,
+	JVM_BUFFER_COUNT:           shapeIntSum,
+	JVM_CLASS_LOADED:           shapeIntSum,
+	JVM_CLASS_UNLOADED:         shapeIntSum,
+	JVM_CLASS_COUNT:            shapeIntSum,
+	JVM_COMPILATION_TIME:       shapeDoubleSum,
+
+	JVM_MEMORY_USED_AFTER_LAST_GC: shapeIntSum,
+
+	DB_CLIENT_CONNECTIONS_USAGE:            shapeIntSum,
+	DB_CLIENT_CONNECTIONS_MAX:              shapeIntSum,
+	DB_CLIENT_CONNECTIONS_PENDING_REQUESTS: shapeIntSum,
+	DB_CLIENT_CONNECTIONS_WAIT_TIME:        shapeHistogram,
+	DB_CLIENT_CONNECTIONS_USE_TIME:         shapeHistogram,
+}
+
+// dialectRule 描述如何将一个非语义约定的指标转换为语义约定指标
+type dialectRule struct {
+	target string
+	// requires 不为空时，数据点必须带有该属性才适用本规则，用于区分与语义约定同名的指标
+	requires string
+	// attributes 将原属性名映射为语义约定属性名，未列出的属性保持不变
+	attributes map[string]string
+	// timeUnit 为时长类指标在没有 unit 时的默认单位，转换时统一换算为秒
+	timeUnit string
+}
+
+var (
+	legacyMemoryAttributes     = map[string]string{"type": JVM_MEMORY_TYPE, "pool": JVM_MEMORY_POOL_NAME}
+	micrometerMemoryAttributes = map[string]string{"area": JVM_MEMORY_TYPE, "id": JVM_MEMORY_POOL_NAME}
+	micrometerGCAttributes     = map[string]string{"gc": JVM_GC_NAME, "action": JVM_GC_ACTION, "cause": JVM_GC_CAUSE}
+	micrometerBufferAttributes = map[string]string{"id": JVM_BUFFER_POOL_NAME}
+)
+
+// dialectRules 按方言列出源指标名到转换规则的映射
+var dialectRules = map[string]map[string]dialectRule{
+	DialectLegacy: {
+		"process.runtime.jvm.memory.usage":               {target: JVM_MEMORY_USED, attributes: legacyMemoryAttributes},
+		"process.runtime.jvm.memory.committed":           {target: JVM_MEMORY_COMMITTED, attributes: legacyMemoryAttributes},
+		"process.runtime.jvm.memory.limit":               {target: JVM_MEMORY_LIMITI, attributes: legacyMemoryAttributes},
+		"process.runtime.jvm.memory.usage_after_last_gc": {target: JVM_MEMORY_USED_AFTER_LAST_GC, attributes: legacyMemoryAttributes},
+		"process.runtime.jvm.gc.duration":                {target: JVM_GC_DURATION, attributes: map[string]string{"gc": JVM_GC_NAME, "action": JVM_GC_ACTION}, timeUnit: "ms"},
+		"process.runtime.jvm.threads.count":              {target: JVM_THREAD_COUNT, attributes: map[string]string{"daemon": JVM_THREAD_DAEMON}},
+		"process.runtime.jvm.cpu.utilization":            {target: JVM_CPU_RECENT_UTILIZATION},
+		"process.runtime.jvm.system.cpu.utilization":     {target: JVM_SYSTEM_CPU_UTILIZATION},
+		"process.runtime.jvm.buffer.usage":               {target: JVM_BUFFER_MEMORY_USED, attributes: map[string]string{"pool": JVM_BUFFER_POOL_NAME}},
+		"process.runtime.jvm.buffer.limit":               {target: JVM_BUFFER_MEMORY_LIMIT, attributes: map[string]string{"pool": JVM_BUFFER_POOL_NAME}},
+		"process.runtime.jvm.buffer.count":               {target: JVM_BUFFER_COUNT, attributes: map[string]string{"pool": JVM_BUFFER_POOL_NAME}},
+		"process.runtime.jvm.classes.loaded":             {target: JVM_CLASS_LOADED},
+		"process.runtime.jvm.classes.unloaded":           {target: JVM_CLASS_UNLOADED},
+		"process.runtime.jvm.classes.current_loaded":     {target: JVM_CLASS_COUNT},
+	},
+	DialectMicrometer: {
+		"jvm.memory.used":           {target: JVM_MEMORY_USED, requires: "id", attributes: micrometerMemoryAttributes},
+		"jvm.memory.committed":      {target: JVM_MEMORY_COMMITTED, requires: "id", attributes: micrometerMemoryAttributes},
+		"jvm.memory.max":            {target: JVM_MEMORY_LIMITI, attributes: micrometerMemoryAttributes},
+		"jvm.gc.pause":              {target: JVM_GC_DURATION, attributes: micrometerGCAttributes, timeUnit: "ms"},
+		"jvm.threads.live":          {target: JVM_THREAD_COUNT},
+		"jvm.threads.states":        {target: JVM_THREAD_COUNT, attributes: map[string]string{"state": JVM_THREAD_STATE}},
+		"jvm.threads.daemon":        {target: JVM_THREADS_DAEMON},
+		"jvm.threads.peak":          {target: JVM_THREADS_PEAK},
+		"jvm.threads.started":       {target: JVM_THREADS_STARTED},
+		"process.cpu.usage":         {target: JVM_CPU_RECENT_UTILIZATION},
+		"system.cpu.usage":          {target: JVM_SYSTEM_CPU_UTILIZATION},
+		"system.cpu.count":          {target: JVM_CPU_COUNT},
+		"process.cpu.time":          {target: JVM_CPU_TIME, timeUnit: "ns"},
+		"jvm.buffer.memory.used":    {target: JVM_BUFFER_MEMORY_USED, requires: "id", attributes: micrometerBufferAttributes},
+		"jvm.buffer.total.capacity": {target: JVM_BUFFER_MEMORY_LIMIT, attributes: micrometerBufferAttributes},
+		"jvm.buffer.count":          {target: JVM_BUFFER_COUNT, requires: "id", attributes: micrometerBufferAttributes},
+		"jvm.classes.loaded":        {target: JVM_CLASS_COUNT},
+		"jvm.classes.unloaded":      {target: JVM_CLASS_UNLOADED},
+		"jvm.compilation.time":      {target: JVM_COMPILATION_TIME, timeUnit: "ms"},
+	},
+	DialectPrometheus: {
+		"jvm_memory_used_bytes":              {target: JVM_MEMORY_USED, attributes: micrometerMemoryAttributes},
+		"jvm_memory_committed_bytes":         {target: JVM_MEMORY_COMMITTED, attributes: micrometerMemoryAttributes},
+		"jvm_memory_max_bytes":               {target: JVM_MEMORY_LIMITI, attributes: micrometerMemoryAttributes},
+		"jvm_gc_pause_seconds":               {target: JVM_GC_DURATION, attributes: micrometerGCAttributes, timeUnit: "s"},
+		"jvm_threads_live_threads":           {target: JVM_THREAD_COUNT},
+		"jvm_threads_states_threads":         {target: JVM_THREAD_COUNT, attributes: map[string]string{"state": JVM_THREAD_STATE}},
+		"jvm_threads_daemon_threads":         {target: JVM_THREADS_DAEMON},
+		"jvm_threads_peak_threads":           {target: JVM_THREADS_PEAK},
+		"jvm_threads_started_threads":        {target: JVM_THREADS_STARTED},
+		"jvm_threads_started_threads_total":  {target: JVM_THREADS_STARTED},
+		"process_cpu_usage":                  {target: JVM_CPU_RECENT_UTILIZATION},
+		"system_cpu_usage":                   {target: JVM_SYSTEM_CPU_UTILIZATION},
+		"system_cpu_count":                   {target: JVM_CPU_COUNT},
+		"jvm_buffer_memory_used_bytes":       {target: JVM_BUFFER_MEMORY_USED, attributes: micrometerBufferAttributes},
+		"jvm_buffer_total_capacity_bytes":    {target: JVM_BUFFER_MEMORY_LIMIT, attributes: micrometerBufferAttributes},
+		"jvm_buffer_count_buffers":           {target: JVM_BUFFER_COUNT, attributes: micrometerBufferAttributes},
+		"jvm_classes_loaded_classes":         {target: JVM_CLASS_COUNT},
+		"jvm_classes_unloaded_classes":       {target: JVM_CLASS_UNLOADED},
+		"jvm_classes_unloaded_classes_total": {target: JVM_CLASS_UNLOADED},
+		"jvm_compilation_time_ms":            {target: JVM_COMPILATION_TIME, timeUnit: "ms"},
+		"jvm_compilation_time_ms_total":      {target: JVM_COMPILATION_TIME, timeUnit: "ms"},
+	},
+}
+
+// metricNormalizer 按配置的方言将输入指标统一为语义约定的名称、属性、单位和类型
+type metricNormalizer struct {
+	scopes    []string
+	semconv   bool
+	rules     map[string][]dialectRule
+	logger    *zap.Logger
+	telemetry *exporterTelemetry
+	// 已记录过日志的类型不匹配，避免每个批次重复打印
+	reported sync.Map
+}
+
+func newMetricNormalizer(cfg InputConfig, logger *zap.Logger, telemetry *exporterTelemetry) (*metricNormalizer, error) {
+	n := &metricNormalizer{
+		scopes:    cfg.Scopes,
+		rules:     make(map[string][]dialectRule),
+		logger:    logger,
+		telemetry: telemetry,
+	}
+	for _, dialect := range cfg.Dialects {
+		if dialect == DialectSemconv {
+			n.semconv = true
+			continue
+		}
+		rules, ok := dialectRules[dialect]
+		if !ok {
+			return nil, fmt.Errorf("unknown dialect %q", dialect)
+		}
+		for source, rule := range rules {
+			n.rules[source] = append(n.rules[source], rule)
+		}
+	}
+	return n, nil
+}
+
+// matchScope 判断 instrumentation scope 是否需要转换
+func (n *metricNormalizer) matchScope(name string) bool {
+	for _, scope := range n.scopes {
+		switch {
+		case scope == "*":
+			return true
+		case scope == "":
+			if name == "" {
+				return true
+			}
+		case strings.Contains(name, scope):
+			return true
+		}
+	}
+	return false
+}
+
+// accepts 判断指标是否属于已启用的方言，只检查名称和必需的属性，类型在 normalize 中检查
+func (n *metricNormalizer) accepts(metric pmetric.Metric) bool {
+	for _, rule := range n.rules[metric.Name()] {
+		if rule.requires == "" || hasDataPointAttribute(metric, rule.requires) {
+			return true
+		}
+	}
+	_, ok := semconvShapes[metric.Name()]
+	return ok && n.semconv
+}
+
+// normalize 返回语义约定形式的指标，不属于任何已启用方言的指标返回 false。
+// 数值类指标的 Gauge/Sum 与 int/double 差异由转换逻辑兼容，指数直方图和 Summary 转换为直方图，
+// 其他无法转换的类型计入类型不匹配并跳过
+func (n *metricNormalizer) normalize(metric pmetric.Metric) (pmetric.Metric, bool) {
+	for _, rule := range n.rules[metric.Name()] {
+		if rule.requires != "" && !hasDataPointAttribute(metric, rule.requires) {
+			continue
+		}
+		converted := pmetric.NewMetric()
+		if !convertMetric(metric, converted, rule) {
+			n.recordTypeMismatch(metric)
+			return converted, false
+		}
+		return converted, true
+	}
+	shape, ok := semconvShapes[metric.Name()]
+	if !ok || !n.semconv {
+		return metric, false
+	}
+	switch {
+	case shape.metricType == metric.Type():
+		return metric, true
+	case shape.metricType == pmetric.MetricTypeGauge && metric.Type() == pmetric.MetricTypeSum,
+		shape.metricType == pmetric.MetricTypeSum && metric.Type() == pmetric.MetricTypeGauge:
+		return metric, true
+	case shape.metricType == pmetric.MetricTypeHistogram:
+		converted := pmetric.NewMetric()
+		if convertMetric(metric, converted, dialectRule{target: metric.Name()}) {
+			return converted, true
+		}
+	}
+	n.recordTypeMismatch(metric)
+	return metric, false
+}
+
+func (n *metricNormalizer) recordTypeMismatch(metric pmetric.Metric) {
+	metricType := metric.Type().String()
+	n.telemetry.recordTypeMismatch(metricType)
+	if _, loaded := n.reported.LoadOrStore(metric.Name()+"/"+metricType, struct{}{}); !loaded {
+		n.logger.Warn("Skipping JVM metric with unexpected type",
+			zap.String("metric", metric.Name()), zap.String("type", metricType))
+	}
+}
+
+// convertMetric 按规则将 src 转换为 dst，类型无法转换时返回 false
+func convertMetric(src pmetric.Metric, dst pmetric.Metric, rule dialectRule) bool {
+	shape := semconvShapes[rule.target]
+	dst.SetName(rule.target)
+	dst.SetDescription(src.Description())
+	scale := 1.0
+	if rule.timeUnit != "" {
+		scale = secondsPerUnit(src.Unit(), rule.timeUnit)
+		dst.SetUnit("s")
+	} else {
+		dst.SetUnit(src.Unit())
+	}
+
+	switch shape.metricType {
+	case pmetric.MetricTypeSum, pmetric.MetricTypeGauge:
+		var points pmetric.NumberDataPointSlice
+		switch src.Type() {
+		case pmetric.MetricTypeSum:
+			points = src.Sum().DataPoints()
+		case pmetric.MetricTypeGauge:
+			points = src.Gauge().DataPoints()
+		default:
+			return false
+		}
+		var out pmetric.NumberDataPointSlice
+		if shape.metricType == pmetric.MetricTypeSum {
+			sum := dst.SetEmptySum()
+			// Gauge 转为 Sum 时按累计值处理
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			if src.Type() == pmetric.MetricTypeSum {
+				sum.SetAggregationTemporality(src.Sum().AggregationTemporality())
+				sum.SetIsMonotonic(src.Sum().IsMonotonic())
+			}
+			out = sum.DataPoints()
+		} else {
+			out = dst.SetEmptyGauge().DataPoints()
+		}
+		for i := 0; i < points.Len(); i++ {
+			point := points.At(i)
+			converted := out.AppendEmpty()
+			converted.SetStartTimestamp(point.StartTimestamp())
+			converted.SetTimestamp(point.Timestamp())
+			convertAttributes(point.Attributes(), converted.Attributes(), rule.attributes)
+			value := numberDataPointDouble(point) * scale
+			if shape.valueType == pmetric.NumberDataPointValueTypeInt {
+				converted.SetIntValue(int64(math.Round(value)))
+			} else {
+				converted.SetDoubleValue(value)
+			}
+		}
+	case pmetric.MetricTypeHistogram:
+		out := dst.SetEmptyHistogram()
+		switch src.Type() {
+		case pmetric.MetricTypeHistogram:
+			out.SetAggregationTemporality(src.Histogram().AggregationTemporality())
+			points := src.Histogram().DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				point := points.At(i)
+				converted := out.DataPoints().AppendEmpty()
+				converted.SetStartTimestamp(point.StartTimestamp())
+				converted.SetTimestamp(point.Timestamp())
+				convertAttributes(point.Attributes(), converted.Attributes(), rule.attributes)
+				converted.SetCount(point.Count())
+				converted.SetSum(point.Sum() * scale)
+				point.BucketCounts().CopyTo(converted.BucketCounts())
+				for j := 0; j < point.ExplicitBounds().Len(); j++ {
+					converted.ExplicitBounds().Append(point.ExplicitBounds().At(j) * scale)
+				}
+			}
+		case pmetric.MetricTypeExponentialHistogram:
+			// 转换逻辑只使用次数和总时长，指数桶不再保留
+			out.SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+			points := src.ExponentialHistogram().DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				point := points.At(i)
+				converted := out.DataPoints().AppendEmpty()
+				converted.SetStartTimestamp(point.StartTimestamp())
+				converted.SetTimestamp(point.Timestamp())
+				convertAttributes(point.Attributes(), converted.Attributes(), rule.attributes)
+				converted.SetCount(point.Count())
+				converted.SetSum(point.Sum() * scale)
+			}
+		case pmetric.MetricTypeSummary:
+			// Summary 只保留次数和总时长
+			out.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			points := src.Summary().DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				point := points.At(i)
+				converted := out.DataPoints().AppendEmpty()
+				converted.SetStartTimestamp(point.StartTimestamp())
+				converted.SetTimestamp(point.Timestamp())
+				convertAttributes(point.Attributes(), converted.Attributes(), rule.attributes)
+				converted.SetCount(point.Count())
+				converted.SetSum(point.Sum() * scale)
+			}
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// convertAttributes 复制属性并按映射改名，jvm.memory.type 的取值同时改为语义约定的写法
+func convertAttributes(src pcommon.Map, dst pcommon.Map, names map[string]string) {
+	src.Range(func(k string, v pcommon.Value) bool {
+		name := k
+		if mapped, ok := names[k]; ok {
+			name = mapped
+		}
+		// Micrometer 使用 nonheap，语义约定使用 non_heap
+		if name == JVM_MEMORY_TYPE && v.AsString() == "nonheap" {
+			dst.PutStr(name, "non_heap")
+			return true
+		}
+		v.CopyTo(dst.PutEmpty(name))
+		return true
+	})
+}
+
+func hasDataPointAttribute(metric pmetric.Metric, name string) bool {
+	var attributes []pcommon.Map
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		points := metric.Sum().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			attributes = append(attributes, points.At(i).Attributes())
+		}
+	case pmetric.MetricTypeGauge:
+		points := metric.Gauge().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			attributes = append(attributes, points.At(i).Attributes())
+		}
+	}
+	for _, attrs := range attributes {
+		if _, ok := attrs.Get(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// secondsPerUnit 返回时长单位换算为秒的系数，unit 为空或无法识别时使用 fallback
+func secondsPerUnit(unit string, fallback string) float64 {
+	switch unit {
+	case "s", "seconds":
+		return 1
+	case "ms", "milliseconds":
+		return 1e-3
+	case "us", "microseconds":
+		return 1e-6
+	case "ns", "nanoseconds":
+		return 1e-9
+	}
+	if fallback != unit && fallback != "" {
+		return secondsPerUnit(fallback, "")
+	}
+	return 1
+}
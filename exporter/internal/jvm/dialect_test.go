@@ -0,0 +1,359 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func newTestNormalizer(t *testing.T, cfg InputConfig) *metricNormalizer {
+	telemetry, err := newExporterTelemetry(component.TelemetrySettings{})
+	require.NoError(t, err)
+	normalizer, err := newMetricNormalizer(cfg, zap.NewNop(), telemetry)
+	require.NoError(t, err)
+	return normalizer
+}
+
+func TestInputConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     InputConfig
+		wantErr string
+	}{
+		{
+			name: "default",
+			cfg:  NewDefaultInputConfig(),
+		},
+		{
+			name:    "no scopes",
+			cfg:     InputConfig{Dialects: []string{DialectSemconv}},
+			wantErr: "at least one scope must be specified",
+		},
+		{
+			name:    "unknown dialect",
+			cfg:     InputConfig{Scopes: []string{"*"}, Dialects: []string{"dropwizard"}},
+			wantErr: `unknown dialect "dropwizard"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMetricNormalizerMatchScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{name: "substring", scopes: []string{"io.opentelemetry.runtime-telemetry-java"}, scope: "io.opentelemetry.runtime-telemetry-java17", want: true},
+		{name: "no match", scopes: []string{"io.opentelemetry.runtime-telemetry-java"}, scope: "io.opentelemetry.jdbc", want: false},
+		{name: "wildcard", scopes: []string{"*"}, scope: "anything", want: true},
+		{name: "empty scope configured", scopes: []string{""}, scope: "", want: true},
+		{name: "empty entry does not match named scopes", scopes: []string{""}, scope: "io.opentelemetry.jdbc", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := newTestNormalizer(t, InputConfig{Scopes: tt.scopes, Dialects: []string{DialectSemconv}})
+			assert.Equal(t, tt.want, normalizer.matchScope(tt.scope))
+		})
+	}
+}
+
+func TestMetricNormalizerNormalize(t *testing.T) {
+	allDialects := []string{DialectSemconv, DialectLegacy, DialectMicrometer, DialectPrometheus}
+	tests := []struct {
+		name           string
+		dialects       []string
+		metric         pmetric.Metric
+		wantOK         bool
+		wantName       string
+		wantType       pmetric.MetricType
+		wantValue      float64
+		wantUnit       string
+		wantAttributes map[string]any
+	}{
+		{
+			name:           "semconv is kept as is",
+			dialects:       allDialects,
+			metric:         newSumMetric(JVM_MEMORY_USED, "By", 1024, map[string]any{JVM_MEMORY_POOL_NAME: "G1 Eden Space", JVM_MEMORY_TYPE: "heap"}),
+			wantOK:         true,
+			wantName:       JVM_MEMORY_USED,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      1024,
+			wantUnit:       "By",
+			wantAttributes: map[string]any{JVM_MEMORY_POOL_NAME: "G1 Eden Space", JVM_MEMORY_TYPE: "heap"},
+		},
+		{
+			name:           "legacy memory usage",
+			dialects:       allDialects,
+			metric:         newSumMetric("process.runtime.jvm.memory.usage", "By", 2048, map[string]any{"pool": "G1 Old Gen", "type": "heap"}),
+			wantOK:         true,
+			wantName:       JVM_MEMORY_USED,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      2048,
+			wantUnit:       "By",
+			wantAttributes: map[string]any{JVM_MEMORY_POOL_NAME: "G1 Old Gen", JVM_MEMORY_TYPE: "heap"},
+		},
+		{
+			name:           "micrometer gauge becomes a sum with semconv attribute values",
+			dialects:       allDialects,
+			metric:         newGaugeMetric("jvm.memory.used", "bytes", 512, map[string]any{"id": "Metaspace", "area": "nonheap"}),
+			wantOK:         true,
+			wantName:       JVM_MEMORY_USED,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      512,
+			wantUnit:       "bytes",
+			wantAttributes: map[string]any{JVM_MEMORY_POOL_NAME: "Metaspace", JVM_MEMORY_TYPE: "non_heap"},
+		},
+		{
+			name:           "micrometer name without its required attribute is semconv",
+			dialects:       allDialects,
+			metric:         newSumMetric("jvm.memory.used", "By", 4096, map[string]any{JVM_MEMORY_POOL_NAME: "Metaspace"}),
+			wantOK:         true,
+			wantName:       JVM_MEMORY_USED,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      4096,
+			wantUnit:       "By",
+			wantAttributes: map[string]any{JVM_MEMORY_POOL_NAME: "Metaspace"},
+		},
+		{
+			name:           "prometheus gauge is converted",
+			dialects:       allDialects,
+			metric:         newGaugeMetric("jvm_threads_live_threads", "", 42, map[string]any{}),
+			wantOK:         true,
+			wantName:       JVM_THREAD_COUNT,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      42,
+			wantAttributes: map[string]any{},
+		},
+		{
+			name:           "cpu time is converted to seconds",
+			dialects:       allDialects,
+			metric:         newGaugeMetric("process.cpu.time", "ns", 2.5e9, map[string]any{}),
+			wantOK:         true,
+			wantName:       JVM_CPU_TIME,
+			wantType:       pmetric.MetricTypeSum,
+			wantValue:      2.5,
+			wantUnit:       "s",
+			wantAttributes: map[string]any{},
+		},
+		{
+			name:     "disabled dialect",
+			dialects: []string{DialectSemconv},
+			metric:   newSumMetric("process.runtime.jvm.memory.usage", "By", 2048, map[string]any{"pool": "G1 Old Gen"}),
+			wantOK:   false,
+		},
+		{
+			name:     "disabled semconv",
+			dialects: []string{DialectLegacy},
+			metric:   newSumMetric(JVM_MEMORY_USED, "By", 1024, map[string]any{}),
+			wantOK:   false,
+		},
+		{
+			name:     "unknown metric",
+			dialects: allDialects,
+			metric:   newSumMetric("http.server.request.duration", "s", 1, map[string]any{}),
+			wantOK:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := newTestNormalizer(t, InputConfig{Scopes: []string{"*"}, Dialects: tt.dialects})
+			assert.Equal(t, tt.wantOK, normalizer.accepts(tt.metric))
+			got, ok := normalizer.normalize(tt.metric)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.wantName, got.Name())
+			assert.Equal(t, tt.wantType, got.Type())
+			assert.Equal(t, tt.wantUnit, got.Unit())
+			dataPoints := numberDataPoints(got)
+			require.Equal(t, 1, dataPoints.Len())
+			assert.InDelta(t, tt.wantValue, numberDataPointDouble(dataPoints.At(0)), 1e-9)
+			assert.Equal(t, tt.wantAttributes, dataPoints.At(0).Attributes().AsRaw())
+		})
+	}
+}
+
+func TestSecondsPerUnit(t *testing.T) {
+	tests := []struct {
+		unit     string
+		fallback string
+		want     float64
+	}{
+		{unit: "s", want: 1},
+		{unit: "ms", want: 1e-3},
+		{unit: "microseconds", want: 1e-6},
+		{unit: "ns", fallback: "ms", want: 1e-9},
+		{unit: "", fallback: "ms", want: 1e-3},
+		{unit: "{ms}", fallback: "ns", want: 1e-9},
+		{unit: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit+"/"+tt.fallback, func(t *testing.T) {
+			assert.Equal(t, tt.want, secondsPerUnit(tt.unit, tt.fallback))
+		})
+	}
+}
+
+func TestConvertAttributes(t *testing.T) {
+	src := pcommon.NewMap()
+	_ = src.FromRaw(map[string]any{"area": "nonheap", "id": "Metaspace", "application": "demo"})
+	dst := pcommon.NewMap()
+	convertAttributes(src, dst, micrometerMemoryAttributes)
+	assert.Equal(t, map[string]any{
+		JVM_MEMORY_TYPE:      "non_heap",
+		JVM_MEMORY_POOL_NAME: "Metaspace",
+		"application":        "demo",
+	}, dst.AsRaw())
+}
+
+func TestMetricNormalizerNormalizeHistogram(t *testing.T) {
+	tests := []struct {
+		name       string
+		metric     func() pmetric.Metric
+		wantOK     bool
+		wantCount  uint64
+		wantSum    float64
+		wantBounds []float64
+		wantDelta  bool
+	}{
+		{
+			name: "semconv histogram is kept as is",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetName(JVM_GC_DURATION)
+				metric.SetUnit("s")
+				histogram := metric.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				dataPoint := histogram.DataPoints().AppendEmpty()
+				dataPoint.SetCount(3)
+				dataPoint.SetSum(0.3)
+				dataPoint.ExplicitBounds().FromRaw([]float64{0.01, 0.1})
+				return metric
+			},
+			wantOK:     true,
+			wantCount:  3,
+			wantSum:    0.3,
+			wantBounds: []float64{0.01, 0.1},
+			wantDelta:  true,
+		},
+		{
+			name: "semconv exponential histogram keeps count and sum",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetName(JVM_GC_DURATION)
+				metric.SetUnit("s")
+				histogram := metric.SetEmptyExponentialHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dataPoint := histogram.DataPoints().AppendEmpty()
+				dataPoint.SetCount(5)
+				dataPoint.SetSum(1.5)
+				return metric
+			},
+			wantOK:     true,
+			wantCount:  5,
+			wantSum:    1.5,
+			wantBounds: []float64{},
+		},
+		{
+			name: "micrometer summary in milliseconds",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetName("jvm.gc.pause")
+				metric.SetUnit("ms")
+				dataPoint := metric.SetEmptySummary().DataPoints().AppendEmpty()
+				dataPoint.SetCount(4)
+				dataPoint.SetSum(250)
+				dataPoint.Attributes().PutStr("gc", "G1 Young Generation")
+				return metric
+			},
+			wantOK:     true,
+			wantCount:  4,
+			wantSum:    0.25,
+			wantBounds: []float64{},
+		},
+		{
+			name: "prometheus histogram in seconds keeps its buckets",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetName("jvm_gc_pause_seconds")
+				histogram := metric.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dataPoint := histogram.DataPoints().AppendEmpty()
+				dataPoint.SetCount(2)
+				dataPoint.SetSum(0.02)
+				dataPoint.ExplicitBounds().FromRaw([]float64{0.005, 0.05})
+				dataPoint.BucketCounts().FromRaw([]uint64{1, 1, 0})
+				return metric
+			},
+			wantOK:     true,
+			wantCount:  2,
+			wantSum:    0.02,
+			wantBounds: []float64{0.005, 0.05},
+		},
+		{
+			name: "legacy histogram without unit uses milliseconds",
+			metric: func() pmetric.Metric {
+				metric := pmetric.NewMetric()
+				metric.SetName("process.runtime.jvm.gc.duration")
+				histogram := metric.SetEmptyHistogram()
+				histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				dataPoint := histogram.DataPoints().AppendEmpty()
+				dataPoint.SetCount(1)
+				dataPoint.SetSum(40)
+				dataPoint.ExplicitBounds().FromRaw([]float64{10, 100})
+				return metric
+			},
+			wantOK:     true,
+			wantCount:  1,
+			wantSum:    0.04,
+			wantBounds: []float64{0.01, 0.1},
+			wantDelta:  true,
+		},
+		{
+			name: "gauge cannot become a histogram",
+			metric: func() pmetric.Metric {
+				return newGaugeMetric(JVM_GC_DURATION, "s", 1, map[string]any{})
+			},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizer := newTestNormalizer(t, NewDefaultInputConfig())
+			got, ok := normalizer.normalize(tt.metric())
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, pmetric.MetricTypeHistogram, got.Type())
+			assert.Equal(t, JVM_GC_DURATION, got.Name())
+			assert.Equal(t, tt.wantDelta, isDeltaMetric(got))
+			dataPoints := got.Histogram().DataPoints()
+			require.Equal(t, 1, dataPoints.Len())
+			assert.Equal(t, tt.wantCount, dataPoints.At(0).Count())
+			assert.InDelta(t, tt.wantSum, dataPoints.At(0).Sum(), 1e-9)
+			bounds := dataPoints.At(0).ExplicitBounds().AsRaw()
+			require.Len(t, bounds, len(tt.wantBounds))
+			for i := range bounds {
+				assert.InDelta(t, tt.wantBounds[i], bounds[i], 1e-9)
+			}
+		})
+	}
+}
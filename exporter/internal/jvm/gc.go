@@ -0,0 +1,102 @@
+package jvm
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// gcPoint 是一个 jvm.gc.duration 数据点，series 为完整属性组成的序列标识
+type gcPoint struct {
+	name      string
+	series    string
+	count     uint64
+	sum       float64
+	timestamp pcommon.Timestamp
+	isDelta   bool
+}
+
+func newGCPoint(name string, histogram pmetric.Histogram, dataPoint pmetric.HistogramDataPoint) gcPoint {
+	return gcPoint{
+		name:      name,
+		series:    attributesKey(dataPoint.Attributes()),
+		count:     dataPoint.Count(),
+		sum:       dataPoint.Sum(),
+		timestamp: dataPoint.Timestamp(),
+		isDelta:   histogram.AggregationTemporality() == pmetric.AggregationTemporalityDelta,
+	}
+}
+
+// gcSeries 是单个属性序列自进程启动以来的累计值
+type gcSeries struct {
+	name          string
+	count         uint64
+	sum           float64
+	lastTimestamp pcommon.Timestamp
+}
+
+// gcTotals 是单个垃圾收集器合并所有 jvm.gc.action/jvm.gc.cause 序列后的累计值，sum 单位为秒
+type gcTotals struct {
+	count uint64
+	sum   float64
+}
+
+// gcState 保存单个 JVM 各 GC 序列的累计值，进程重启时随 jvmState 一起重置
+type gcState struct {
+	series map[string]*gcSeries
+}
+
+// update 合并本批次的数据点：delta 数据点累加，cumulative 数据点直接替换，
+// 时间戳不大于上次的重复数据点被忽略。返回按垃圾收集器合并后的累计值
+func (s *gcState) update(points []gcPoint) map[string]gcTotals {
+	if s.series == nil {
+		s.series = make(map[string]*gcSeries)
+	}
+	// cumulative 计数变小说明计数器被重置，之前累加的 delta 也不再可信
+	for _, p := range points {
+		if series, ok := s.series[p.series]; ok && !p.isDelta && p.timestamp > series.lastTimestamp && p.count < series.count {
+			s.series = make(map[string]*gcSeries)
+			break
+		}
+	}
+	for _, p := range points {
+		series, ok := s.series[p.series]
+		if !ok {
+			series = &gcSeries{name: p.name}
+			s.series[p.series] = series
+		}
+		if p.timestamp != 0 && p.timestamp <= series.lastTimestamp {
+			continue
+		}
+		if p.isDelta {
+			series.count += p.count
+			series.sum += p.sum
+		} else {
+			series.count = p.count
+			series.sum = p.sum
+		}
+		series.lastTimestamp = p.timestamp
+	}
+
+	totals := make(map[string]gcTotals)
+	for _, series := range s.series {
+		total := totals[series.name]
+		total.count += series.count
+		total.sum += series.sum
+		totals[series.name] = total
+	}
+	return totals
+}
+
+// attributesKey 将属性按名称排序后拼接为序列标识
+func attributesKey(attributes pcommon.Map) string {
+	parts := make([]string, 0, attributes.Len())
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		parts = append(parts, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,99 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestGCStateUpdate(t *testing.T) {
+	const young = "G1 Young Generation"
+	const old = "G1 Old Generation"
+	point := func(name, series string, count uint64, sum float64, ts pcommon.Timestamp, isDelta bool) gcPoint {
+		return gcPoint{name: name, series: series, count: count, sum: sum, timestamp: ts, isDelta: isDelta}
+	}
+	tests := []struct {
+		name    string
+		batches [][]gcPoint
+		want    map[string]gcTotals
+	}{
+		{
+			name: "cumulative points replace the series",
+			batches: [][]gcPoint{
+				{point(young, "a", 2, 0.5, 10, false)},
+				{point(young, "a", 5, 1.5, 20, false)},
+			},
+			want: map[string]gcTotals{young: {count: 5, sum: 1.5}},
+		},
+		{
+			name: "delta points are added up",
+			batches: [][]gcPoint{
+				{point(young, "a", 2, 0.5, 10, true)},
+				{point(young, "a", 3, 1, 20, true)},
+			},
+			want: map[string]gcTotals{young: {count: 5, sum: 1.5}},
+		},
+		{
+			name: "duplicate points are ignored",
+			batches: [][]gcPoint{
+				{point(young, "a", 2, 0.5, 10, true)},
+				{point(young, "a", 2, 0.5, 10, true)},
+			},
+			want: map[string]gcTotals{young: {count: 2, sum: 0.5}},
+		},
+		{
+			name: "series of one collector are merged",
+			batches: [][]gcPoint{
+				{
+					point(young, "action=minor,cause=G1 Evacuation Pause", 4, 1, 10, false),
+					point(young, "action=minor,cause=Metadata GC Threshold", 1, 0.25, 10, false),
+					point(old, "action=major,cause=System.gc()", 1, 2, 10, false),
+				},
+			},
+			want: map[string]gcTotals{
+				young: {count: 5, sum: 1.25},
+				old:   {count: 1, sum: 2},
+			},
+		},
+		{
+			name: "counter reset drops accumulated deltas",
+			batches: [][]gcPoint{
+				{point(young, "a", 10, 4, 10, false), point(young, "b", 3, 1, 10, true)},
+				{point(young, "a", 2, 0.5, 20, false)},
+			},
+			want: map[string]gcTotals{young: {count: 2, sum: 0.5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state gcState
+			var got map[string]gcTotals
+			for _, batch := range tt.batches {
+				got = state.update(batch)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewGCPoint(t *testing.T) {
+	histogram := pmetric.NewHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dataPoint := histogram.DataPoints().AppendEmpty()
+	dataPoint.Attributes().PutStr("jvm.gc.name", "ZGC Pauses")
+	dataPoint.Attributes().PutStr("jvm.gc.action", "end of GC pause")
+	dataPoint.SetCount(3)
+	dataPoint.SetSum(0.003)
+	dataPoint.SetTimestamp(42)
+
+	assert.Equal(t, gcPoint{
+		name:      "ZGC Pauses",
+		series:    "jvm.gc.action=end of GC pause,jvm.gc.name=ZGC Pauses",
+		count:     3,
+		sum:       0.003,
+		timestamp: 42,
+		isDelta:   true,
+	}, newGCPoint("ZGC Pauses", histogram, dataPoint))
+}
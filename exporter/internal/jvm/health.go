@@ -0,0 +1,245 @@
+package jvm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	JVM_MEMORY_USED_AFTER_LAST_GC = "jvm.memory.used_after_last_gc"
+
+	jvmMemoryTypeHeap = "heap"
+
+	// Status 字段的取值
+	healthStatusOK       = 0
+	healthStatusWarning  = 1
+	healthStatusCritical = 2
+	// JVM 超过 offline.timeout 未上报
+	HealthStatusOffline = 3
+
+	healthRuleHeapUtilization = "heap_utilization"
+	healthRuleOldGenAfterGC   = "old_gen_after_gc"
+	healthRuleGCTimeRatio     = "gc_time_ratio"
+	healthRuleThreadCount     = "thread_count"
+	healthRuleProcessCPU      = "process_cpu"
+)
+
+// HealthThreshold defines the warning and critical levels of a health rule.
+// A level of 0 disables it.
+type HealthThreshold struct {
+	// Warning is the value at or above which the JVM is reported with status 1.
+	Warning float64 `mapstructure:"warning"`
+
+	// Critical is the value at or above which the JVM is reported with status 2.
+	Critical float64 `mapstructure:"critical"`
+}
+
+func (t HealthThreshold) validate(maxValue float64) error {
+	if t.Warning < 0 || t.Critical < 0 {
+		return fmt.Errorf("thresholds must not be negative")
+	}
+	if maxValue > 0 && (t.Warning > maxValue || t.Critical > maxValue) {
+		return fmt.Errorf("thresholds must not exceed %g", maxValue)
+	}
+	if t.Warning > 0 && t.Critical > 0 && t.Critical < t.Warning {
+		return fmt.Errorf("critical must not be lower than warning")
+	}
+	return nil
+}
+
+// level 返回 value 触发的状态等级
+func (t HealthThreshold) level(value float64) int {
+	switch {
+	case t.Critical > 0 && value >= t.Critical:
+		return healthStatusCritical
+	case t.Warning > 0 && value >= t.Warning:
+		return healthStatusWarning
+	}
+	return healthStatusOK
+}
+
+// HealthConfig defines the rules used to compute the status field of JVM payloads.
+//
+// Each interval every rule with data is evaluated, the status is the highest level triggered
+// and the names of the triggering rules are reported in statusReasons.
+type HealthConfig struct {
+	// Enabled turns status computation on or off. When disabled the status is always 0.
+	Enabled bool `mapstructure:"enabled"`
+
+	// HeapUtilization is the ratio of used heap to the heap limit, in [0, 1].
+	HeapUtilization HealthThreshold `mapstructure:"heap_utilization"`
+
+	// OldGenAfterGC is the ratio of old generation memory still used after the last
+	// collection to the old generation limit, in [0, 1].
+	OldGenAfterGC HealthThreshold `mapstructure:"old_gen_after_gc"`
+
+	// GCTimeRatio is the fraction of wall time spent in garbage collection since the
+	// previous report, in [0, 1].
+	GCTimeRatio HealthThreshold `mapstructure:"gc_time_ratio"`
+
+	// ThreadCount is the number of live threads.
+	ThreadCount HealthThreshold `mapstructure:"thread_count"`
+
+	// ProcessCPU is the process CPU utilization averaged over cpu.average_window, in [0, 1].
+	ProcessCPU HealthThreshold `mapstructure:"process_cpu"`
+}
+
+func NewDefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Enabled:         true,
+		HeapUtilization: HealthThreshold{Warning: 0.85, Critical: 0.95},
+		OldGenAfterGC:   HealthThreshold{Warning: 0.7, Critical: 0.9},
+		GCTimeRatio:     HealthThreshold{Warning: 0.1, Critical: 0.25},
+		ThreadCount:     HealthThreshold{Warning: 1000, Critical: 2000},
+		ProcessCPU:      HealthThreshold{Warning: 0.8, Critical: 0.95},
+	}
+}
+
+// Validate checks the health rule thresholds.
+func (cfg *HealthConfig) Validate() error {
+	for _, rule := range cfg.rules() {
+		if err := rule.threshold.validate(rule.maxValue); err != nil {
+			return fmt.Errorf("%s: %w", rule.name, err)
+		}
+	}
+	return nil
+}
+
+type healthRule struct {
+	name      string
+	threshold HealthThreshold
+	// maxValue 为比例类规则的上限，0 表示不限制
+	maxValue float64
+}
+
+// rules 按上报 statusReasons 的顺序返回所有规则
+func (cfg *HealthConfig) rules() []healthRule {
+	return []healthRule{
+		{name: healthRuleHeapUtilization, threshold: cfg.HeapUtilization, maxValue: 1},
+		{name: healthRuleOldGenAfterGC, threshold: cfg.OldGenAfterGC, maxValue: 1},
+		{name: healthRuleGCTimeRatio, threshold: cfg.GCTimeRatio, maxValue: 1},
+		{name: healthRuleThreadCount, threshold: cfg.ThreadCount},
+		{name: healthRuleProcessCPU, threshold: cfg.ProcessCPU, maxValue: 1},
+	}
+}
+
+// evaluate 根据本周期的规则输入计算状态码和触发的规则名，没有数据的规则不参与计算
+func (cfg *HealthConfig) evaluate(values map[string]float64) (int, []string) {
+	if !cfg.Enabled {
+		return healthStatusOK, nil
+	}
+	status := healthStatusOK
+	var reasons []string
+	for _, rule := range cfg.rules() {
+		value, ok := values[rule.name]
+		if !ok {
+			continue
+		}
+		if level := rule.threshold.level(value); level > healthStatusOK {
+			reasons = append(reasons, rule.name)
+			status = max(status, level)
+		}
+	}
+	return status, reasons
+}
+
+// memoryPoolHealth 是单个内存池在一个批次中用于健康计算的值
+type memoryPoolHealth struct {
+	heap        bool
+	used        int64
+	limit       int64
+	usedAfterGC int64
+	hasAfterGC  bool
+}
+
+// healthObservation 收集内存池的类型和 GC 后使用量，这些信息不会出现在内部格式中
+type healthObservation struct {
+	pools map[string]*memoryPoolHealth
+}
+
+func (o *healthObservation) addMemory(metricName string, dataPoint pmetric.NumberDataPoint) {
+	name, ok := dataPoint.Attributes().Get(JVM_MEMORY_POOL_NAME)
+	if !ok {
+		return
+	}
+	if o.pools == nil {
+		o.pools = make(map[string]*memoryPoolHealth)
+	}
+	pool, ok := o.pools[name.AsString()]
+	if !ok {
+		pool = &memoryPoolHealth{}
+		o.pools[name.AsString()] = pool
+	}
+	if memoryType, ok := dataPoint.Attributes().Get(JVM_MEMORY_TYPE); ok && memoryType.AsString() == jvmMemoryTypeHeap {
+		pool.heap = true
+	}
+	switch metricName {
+	case JVM_MEMORY_USED:
+		pool.used = numberDataPointInt(dataPoint)
+	case JVM_MEMORY_LIMITI:
+		pool.limit = numberDataPointInt(dataPoint)
+	case JVM_MEMORY_USED_AFTER_LAST_GC:
+		pool.usedAfterGC = numberDataPointInt(dataPoint)
+		pool.hasAfterGC = true
+	}
+}
+
+// heapUtilization 返回堆使用率。G1 等收集器只有老年代带有上限（即最大堆），
+// 因此分母取所有带上限的堆内存池上限之和
+func (o *healthObservation) heapUtilization() (float64, bool) {
+	var used, limit int64
+	for _, pool := range o.pools {
+		if !pool.heap {
+			continue
+		}
+		used += pool.used
+		if pool.limit > 0 {
+			limit += pool.limit
+		}
+	}
+	if limit == 0 {
+		return 0, false
+	}
+	return float64(used) / float64(limit), true
+}
+
+// oldGenAfterGC 返回老年代 GC 后的使用率
+func (o *healthObservation) oldGenAfterGC() (float64, bool) {
+	var used, limit int64
+	for name, pool := range o.pools {
+		if !pool.hasAfterGC || pool.limit <= 0 || !isOldGenPool(name) {
+			continue
+		}
+		used += pool.usedAfterGC
+		limit += pool.limit
+	}
+	if limit == 0 {
+		return 0, false
+	}
+	return float64(used) / float64(limit), true
+}
+
+// isOldGenPool 根据 HotSpot 和 OpenJ9 的内存池命名识别老年代
+func isOldGenPool(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "old") || strings.Contains(name, "tenured")
+}
+
+// healthState 保存上次上报时的 GC 累计耗时，用于计算 GC 时间占比
+type healthState struct {
+	gcTime      float64
+	gcTimestamp time.Time
+}
+
+// gcTimeRatio 返回两次上报之间 GC 耗时占墙钟时间的比例，gcTime 单位为秒
+func (s *healthState) gcTimeRatio(gcTime float64, timestamp time.Time) (float64, bool) {
+	last, lastTimestamp := s.gcTime, s.gcTimestamp
+	s.gcTime, s.gcTimestamp = gcTime, timestamp
+	if lastTimestamp.IsZero() || !timestamp.After(lastTimestamp) || gcTime < last {
+		return 0, false
+	}
+	return (gcTime - last) / timestamp.Sub(lastTimestamp).Seconds(), true
+}
@@ -0,0 +1,251 @@
+package jvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestHealthConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*HealthConfig)
+		wantErr string
+	}{
+		{
+			name:   "default",
+			modify: func(*HealthConfig) {},
+		},
+		{
+			name:    "negative threshold",
+			modify:  func(cfg *HealthConfig) { cfg.ThreadCount.Warning = -1 },
+			wantErr: "thread_count: thresholds must not be negative",
+		},
+		{
+			name:    "ratio above one",
+			modify:  func(cfg *HealthConfig) { cfg.HeapUtilization.Critical = 1.5 },
+			wantErr: "heap_utilization: thresholds must not exceed 1",
+		},
+		{
+			name:   "thread count is not a ratio",
+			modify: func(cfg *HealthConfig) { cfg.ThreadCount = HealthThreshold{Warning: 5000, Critical: 10000} },
+		},
+		{
+			name:    "critical below warning",
+			modify:  func(cfg *HealthConfig) { cfg.GCTimeRatio = HealthThreshold{Warning: 0.5, Critical: 0.2} },
+			wantErr: "gc_time_ratio: critical must not be lower than warning",
+		},
+		{
+			name:   "critical only",
+			modify: func(cfg *HealthConfig) { cfg.ProcessCPU = HealthThreshold{Critical: 0.9} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultHealthConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestHealthConfigEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		values      map[string]float64
+		wantStatus  int
+		wantReasons []string
+	}{
+		{
+			name:       "no data",
+			enabled:    true,
+			wantStatus: healthStatusOK,
+		},
+		{
+			name:       "below every threshold",
+			enabled:    true,
+			values:     map[string]float64{healthRuleHeapUtilization: 0.5, healthRuleThreadCount: 100},
+			wantStatus: healthStatusOK,
+		},
+		{
+			name:        "warning",
+			enabled:     true,
+			values:      map[string]float64{healthRuleHeapUtilization: 0.85},
+			wantStatus:  healthStatusWarning,
+			wantReasons: []string{healthRuleHeapUtilization},
+		},
+		{
+			name:    "highest level wins and reasons keep the rule order",
+			enabled: true,
+			values: map[string]float64{
+				healthRuleProcessCPU:      0.85,
+				healthRuleGCTimeRatio:     0.3,
+				healthRuleHeapUtilization: 0.2,
+			},
+			wantStatus:  healthStatusCritical,
+			wantReasons: []string{healthRuleGCTimeRatio, healthRuleProcessCPU},
+		},
+		{
+			name:       "disabled",
+			enabled:    false,
+			values:     map[string]float64{healthRuleThreadCount: 5000},
+			wantStatus: healthStatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultHealthConfig()
+			cfg.Enabled = tt.enabled
+			status, reasons := cfg.evaluate(tt.values)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantReasons, reasons)
+		})
+	}
+}
+
+func TestHealthThresholdLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold HealthThreshold
+		value     float64
+		want      int
+	}{
+		{name: "below warning", threshold: HealthThreshold{Warning: 10, Critical: 20}, value: 9, want: healthStatusOK},
+		{name: "at warning", threshold: HealthThreshold{Warning: 10, Critical: 20}, value: 10, want: healthStatusWarning},
+		{name: "at critical", threshold: HealthThreshold{Warning: 10, Critical: 20}, value: 20, want: healthStatusCritical},
+		{name: "warning disabled", threshold: HealthThreshold{Critical: 20}, value: 15, want: healthStatusOK},
+		{name: "disabled", threshold: HealthThreshold{}, value: 100, want: healthStatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.threshold.level(tt.value))
+		})
+	}
+}
+
+func TestHealthObservation(t *testing.T) {
+	type memoryPoint struct {
+		metric string
+		pool   string
+		heap   bool
+		value  int64
+	}
+	tests := []struct {
+		name           string
+		points         []memoryPoint
+		wantHeap       float64
+		wantHeapOK     bool
+		wantOldGenGC   float64
+		wantOldGenGCOK bool
+	}{
+		{
+			name: "G1 heap limit comes from the old generation",
+			points: []memoryPoint{
+				{metric: JVM_MEMORY_USED, pool: "G1 Eden Space", heap: true, value: 300},
+				{metric: JVM_MEMORY_USED, pool: "G1 Old Gen", heap: true, value: 500},
+				{metric: JVM_MEMORY_LIMITI, pool: "G1 Old Gen", heap: true, value: 1000},
+				{metric: JVM_MEMORY_USED_AFTER_LAST_GC, pool: "G1 Old Gen", heap: true, value: 400},
+				{metric: JVM_MEMORY_USED, pool: "Metaspace", value: 10000},
+				{metric: JVM_MEMORY_LIMITI, pool: "Metaspace", value: 20000},
+			},
+			wantHeap:       0.8,
+			wantHeapOK:     true,
+			wantOldGenGC:   0.4,
+			wantOldGenGCOK: true,
+		},
+		{
+			name: "OpenJ9 tenured pool",
+			points: []memoryPoint{
+				{metric: JVM_MEMORY_USED, pool: "tenured-SOA", heap: true, value: 100},
+				{metric: JVM_MEMORY_LIMITI, pool: "tenured-SOA", heap: true, value: 400},
+				{metric: JVM_MEMORY_USED_AFTER_LAST_GC, pool: "tenured-SOA", heap: true, value: 50},
+			},
+			wantHeap:       0.25,
+			wantHeapOK:     true,
+			wantOldGenGC:   0.125,
+			wantOldGenGCOK: true,
+		},
+		{
+			name: "no limits",
+			points: []memoryPoint{
+				{metric: JVM_MEMORY_USED, pool: "G1 Eden Space", heap: true, value: 300},
+				{metric: JVM_MEMORY_USED_AFTER_LAST_GC, pool: "G1 Old Gen", heap: true, value: 400},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var obs healthObservation
+			for _, p := range tt.points {
+				dataPoint := pmetric.NewNumberDataPoint()
+				dataPoint.SetIntValue(p.value)
+				dataPoint.Attributes().PutStr(JVM_MEMORY_POOL_NAME, p.pool)
+				if p.heap {
+					dataPoint.Attributes().PutStr(JVM_MEMORY_TYPE, jvmMemoryTypeHeap)
+				}
+				obs.addMemory(p.metric, dataPoint)
+			}
+			heap, ok := obs.heapUtilization()
+			assert.Equal(t, tt.wantHeapOK, ok)
+			assert.InDelta(t, tt.wantHeap, heap, 1e-9)
+			oldGen, ok := obs.oldGenAfterGC()
+			assert.Equal(t, tt.wantOldGenGCOK, ok)
+			assert.InDelta(t, tt.wantOldGenGC, oldGen, 1e-9)
+		})
+	}
+}
+
+func TestHealthStateGCTimeRatio(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	type report struct {
+		gcTime    float64
+		timestamp time.Time
+		want      float64
+		wantOK    bool
+	}
+	tests := []struct {
+		name    string
+		reports []report
+	}{
+		{
+			name: "ratio between two reports",
+			reports: []report{
+				{gcTime: 1, timestamp: start},
+				{gcTime: 4, timestamp: start.Add(30 * time.Second), want: 0.1, wantOK: true},
+			},
+		},
+		{
+			name: "counter reset",
+			reports: []report{
+				{gcTime: 10, timestamp: start},
+				{gcTime: 2, timestamp: start.Add(30 * time.Second)},
+				{gcTime: 5, timestamp: start.Add(60 * time.Second), want: 0.1, wantOK: true},
+			},
+		},
+		{
+			name: "same timestamp",
+			reports: []report{
+				{gcTime: 1, timestamp: start},
+				{gcTime: 2, timestamp: start},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state healthState
+			for _, r := range tt.reports {
+				got, ok := state.gcTimeRatio(r.gcTime, r.timestamp)
+				assert.Equal(t, r.wantOK, ok)
+				assert.InDelta(t, r.want, got, 1e-9)
+			}
+		})
+	}
+}
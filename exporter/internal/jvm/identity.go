@@ -0,0 +1,128 @@
+package jvm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const defaultAgentIDTemplate = "${service.name:-unknown}-${k8s.pod.name|host.name:-unknown}-${service.instance.id|process.pid}@${host.ip|k8s.pod.ip|net.host.ip:-127.0.0.1}:${server.port:-0}"
+
+// IdentityConfig defines how agentId and multiAgentId are derived from resource attributes.
+//
+// Templates reference resource attributes as ${name}. Alternatives separated by "|" are tried in
+// order and ":-" introduces a literal used when none of them is present, e.g.
+// "${k8s.pod.name|host.name:-unknown}". A placeholder without a default renders as an empty string.
+type IdentityConfig struct {
+	// AgentID is the template for agentId. If it renders empty the resource identity
+	// (service.instance.id, or service.name/host.name/process.pid) is used instead.
+	AgentID string `mapstructure:"agent_id"`
+
+	// MultiAgentID is the template for multiAgentId. If omitted the rendered agentId is used.
+	MultiAgentID string `mapstructure:"multi_agent_id"`
+}
+
+func NewDefaultIdentityConfig() IdentityConfig {
+	return IdentityConfig{
+		AgentID: defaultAgentIDTemplate,
+	}
+}
+
+// Identity 是编译后的身份模板
+type Identity struct {
+	agentID      *Template
+	multiAgentID *Template
+}
+
+func NewIdentity(cfg IdentityConfig) (*Identity, error) {
+	var err error
+	id := &Identity{}
+	if id.agentID, err = ParseTemplate(cfg.AgentID); err != nil {
+		return nil, fmt.Errorf("invalid agent_id template: %w", err)
+	}
+	if id.multiAgentID, err = ParseTemplate(cfg.MultiAgentID); err != nil {
+		return nil, fmt.Errorf("invalid multi_agent_id template: %w", err)
+	}
+	return id, nil
+}
+
+// AgentIDs 渲染 agentId 和 multiAgentId，key 为模板渲染为空时的兜底值
+func (id *Identity) AgentIDs(attributes pcommon.Map, key string) (string, string) {
+	agentID := id.agentID.Render(attributes)
+	if agentID == "" {
+		agentID = key
+	}
+	multiAgentID := id.multiAgentID.Render(attributes)
+	if multiAgentID == "" {
+		multiAgentID = agentID
+	}
+	return agentID, multiAgentID
+}
+
+type templatePart struct {
+	literal    string
+	attributes []string
+	fallback   string
+}
+
+// Template 是以 ${name} 引用资源属性的模板，语法见 IdentityConfig
+type Template struct {
+	parts []templatePart
+}
+
+func ParseTemplate(template string) (*Template, error) {
+	t := &Template{}
+	text := template
+	for text != "" {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			t.parts = append(t.parts, templatePart{literal: text})
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, templatePart{literal: text[:start]})
+		}
+		end := strings.Index(text[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in %q", template)
+		}
+		expr := text[start+2 : start+end]
+		text = text[start+end+1:]
+
+		part := templatePart{}
+		if i := strings.Index(expr, ":-"); i >= 0 {
+			part.fallback = expr[i+2:]
+			expr = expr[:i]
+		}
+		for _, name := range strings.Split(expr, "|") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil, errors.New("empty attribute name in placeholder")
+			}
+			part.attributes = append(part.attributes, name)
+		}
+		t.parts = append(t.parts, part)
+	}
+	return t, nil
+}
+
+func (t *Template) Render(attributes pcommon.Map) string {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		if part.attributes == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+		value := part.fallback
+		for _, name := range part.attributes {
+			if v, ok := attributes.Get(name); ok && v.AsString() != "" {
+				value = v.AsString()
+				break
+			}
+		}
+		sb.WriteString(value)
+	}
+	return sb.String()
+}
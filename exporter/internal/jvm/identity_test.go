@@ -0,0 +1,64 @@
+package jvm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "empty", text: ""},
+		{name: "literal", text: "bookdemo"},
+		{name: "placeholders", text: "${service.name:-unknown}@${host.ip|k8s.pod.ip}"},
+		{name: "unclosed placeholder", text: "${service.name}@${host.ip", wantErr: `unclosed placeholder in "${service.name}@${host.ip"`},
+		{name: "empty placeholder", text: "${}", wantErr: "empty attribute name in placeholder"},
+		{name: "empty alternative", text: "${host.ip||k8s.pod.ip}", wantErr: "empty attribute name in placeholder"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.text)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	attributes := pcommon.NewMap()
+	attributes.PutStr("service.name", "bookdemo")
+	attributes.PutStr("k8s.pod.ip", "10.0.0.7")
+	attributes.PutStr("host.name", "")
+	attributes.PutInt("process.pid", 4242)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "literal", text: "bookdemo", want: "bookdemo"},
+		{name: "attribute", text: "${service.name}", want: "bookdemo"},
+		{name: "non string attribute", text: "pid-${process.pid}", want: "pid-4242"},
+		{name: "first present alternative", text: "${host.ip|k8s.pod.ip}", want: "10.0.0.7"},
+		{name: "missing attribute", text: "${host.ip}:${server.port}", want: ":"},
+		{name: "default", text: "${host.ip:-127.0.0.1}:${server.port:-0}", want: "127.0.0.1:0"},
+		{name: "empty attribute uses default", text: "${host.name:-unknown}", want: "unknown"},
+		{name: "spaces around alternatives", text: "${ host.ip | service.name }", want: "bookdemo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseTemplate(tt.text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, template.Render(attributes))
+		})
+	}
+}
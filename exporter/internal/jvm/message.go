@@ -0,0 +1,114 @@
+package jvm
+
+// 内部格式的数据结构
+type JManagementMessage struct {
+	BufferPool struct {
+		Mapped BufferPoolInfo `json:"mapped"`
+		Direct BufferPoolInfo `json:"direct"`
+	} `json:"bufferPool"`
+	AgentId                   string           `json:"agentId"`
+	CreationTime              string           `json:"creationTime"`
+	AppName                   string           `json:"appName"`
+	AppStartTime              string           `json:"appStartTime"`
+	CPU                       CPU              `json:"cpu"`
+	Pid                       string           `json:"pid"`
+	Thread                    Thread           `json:"thread"`
+	MemoryPool                MemoryPool       `json:"memoryPool"`
+	Version                   string           `json:"version"`
+	Docker                    bool             `json:"docker"`
+	GarbageCollector          GarbageCollector `json:"garbageCollector"`
+	MultiAgentId              string           `json:"multiAgentId"`
+	DatabaseConnectionMessage struct {
+		LeakSuspicious                 []LeakSuspicion      `json:"leakSuspicious"`
+		DatabaseConnectionMessageArray []DatabaseConnection `json:"databaseConnectionMessageArray"`
+	} `json:"databaseConnectionMessage"`
+	Status        int          `json:"status"`
+	StatusReasons []string     `json:"statusReasons"` // 触发 Status 的健康规则名
+	ClassLoading  ClassLoading `json:"classLoading"`
+	Compilation   Compilation  `json:"compilation"`
+}
+
+type BufferPoolInfo struct {
+	Count    int64 `json:"count"`
+	Used     int64 `json:"used"`
+	Capacity int64 `json:"capacity"`
+}
+
+// waitTime 和 useTime 为上报周期内的平均值，单位为毫秒
+type DatabaseConnection struct {
+	PoolName        string  `json:"poolName"`
+	Idle            int64   `json:"idle"`
+	Used            int64   `json:"used"`
+	Max             int64   `json:"max"`
+	PendingRequests int64   `json:"pendingRequests"`
+	WaitTime        float64 `json:"waitTime"`
+	UseTime         float64 `json:"useTime"`
+}
+
+type LeakSuspicion struct {
+	PoolName  string  `json:"poolName"`
+	Reason    string  `json:"reason"`
+	Intervals int     `json:"intervals"`
+	Used      int64   `json:"used"`
+	Max       int64   `json:"max"`
+	WaitTime  float64 `json:"waitTime"`
+}
+
+type ClassLoading struct {
+	LoadedClassCount      int64 `json:"loadedClassCount"`
+	TotalLoadedClassCount int64 `json:"totalLoadedClassCount"`
+	UnloadedClassCount    int64 `json:"unloadedClassCount"`
+}
+
+// Compilation 来自 Micrometer 的 JvmCompilationMetrics，TotalCompilationTime 单位为毫秒
+type Compilation struct {
+	Name                 string `json:"name"`
+	TotalCompilationTime int64  `json:"totalCompilationTime"`
+}
+
+type CPU struct {
+	ProcessCpu    float64 `json:"processCpu"`
+	AvgSystemCpu  float64 `json:"avgSystemCpu"`
+	SystemCpu     float64 `json:"systemCpu"`
+	AvgProcessCpu float64 `json:"avgProcessCpu"`
+}
+
+type Thread struct {
+	ThreadCount             int64       `json:"threadCount"`
+	ThreadInfos             ThreadInfos `json:"threadInfos"`
+	TotalStartedThreadCount int64       `json:"totalStartedThreadCount"`
+	PeakThreadCount         int64       `json:"peakThreadCount"`
+	DeamonThreadCount       int64       `json:"deamonThreadCount"`
+	// 按 java.lang.Thread.State 统计的线程数
+	StateCounts map[string]int64 `json:"stateCounts"`
+}
+
+// ThreadInfo 的每一行依次为 threadId, threadName, threadState, lockName, lockOwnerId,
+// lockOwnerName, deadlocked, stackTrace
+type ThreadInfos struct {
+	ThreadInfo [][]interface{} `json:"threadInfo"`
+	LockNames  []string        `json:"lockNames"`
+}
+
+type MemoryPool struct {
+	MemoryUsages map[string]*MemoryUsage `json:"memoryUsages"`
+}
+
+type MemoryUsage struct {
+	Init      int64 `json:"init"`
+	Committed int64 `json:"committed"`
+	Max       int64 `json:"max"`
+	Used      int64 `json:"used"`
+}
+
+type GarbageCollector struct {
+	GarbageCollectors map[string]*GarbageCollectorInfo `json:"garbageCollectors"`
+}
+
+type GarbageCollectorInfo struct {
+	Valid           bool     `json:"valid"`
+	CollectionTime  int      `json:"collectionTime"`
+	MemoryPoolNames []string `json:"memoryPoolNames"`
+	CollectionCount uint64   `json:"collectionCount"`
+	Name            string   `json:"name"`
+}
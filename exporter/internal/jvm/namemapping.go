@@ -0,0 +1,249 @@
+package jvm
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// NameMappingConfig defines how JVM memory pool and garbage collector names are translated
+// into the names expected by the backend dashboards.
+//
+// Mappings are applied in order: built-in profiles, then File, then the inline maps, so later
+// sources override earlier ones. Names without a mapping are reported unchanged.
+type NameMappingConfig struct {
+	// Profiles lists the built-in presets to load. Valid values are "g1", "parallel", "cms",
+	// "serial", "zgc", "shenandoah" and "openj9". Pools shared by every HotSpot collector
+	// (Metaspace, CodeHeap, ...) are always loaded. Defaults to all presets.
+	Profiles []string `mapstructure:"profiles"`
+
+	// File is an optional YAML or JSON file with "memory_pools" and "garbage_collectors" maps.
+	File string `mapstructure:"file"`
+
+	// MemoryPools maps jvm.memory.pool.name values to backend pool names.
+	MemoryPools map[string]string `mapstructure:"memory_pools"`
+
+	// GarbageCollectors maps jvm.gc.name values to backend collector names.
+	GarbageCollectors map[string]string `mapstructure:"garbage_collectors"`
+}
+
+func NewDefaultNameMappingConfig() NameMappingConfig {
+	return NameMappingConfig{
+		Profiles: builtinProfileNames(),
+	}
+}
+
+// Validate checks that all referenced built-in profiles exist.
+func (cfg *NameMappingConfig) Validate() error {
+	for _, profile := range cfg.Profiles {
+		if _, ok := builtinNameProfiles[profile]; !ok {
+			return fmt.Errorf("unknown name mapping profile %q", profile)
+		}
+	}
+	return nil
+}
+
+type nameProfile struct {
+	memoryPools       map[string]string
+	garbageCollectors map[string]string
+}
+
+const commonNameProfile = "common"
+
+var builtinNameProfiles = map[string]nameProfile{
+	// 所有 HotSpot 收集器共有的非堆内存池；JDK 8 以及关闭分段代码缓存时只有一个 Code Cache，
+	// 否则拆分为三个 CodeHeap
+	commonNameProfile: {
+		memoryPools: map[string]string{
+			"Metaspace":                        "Metaspace",
+			"Compressed Class Space":           "CompressedClassSpace",
+			"Code Cache":                       "CodeCache",
+			"CodeHeap 'non-nmethods'":          "CodeHeap'non-nmethods'",
+			"CodeHeap 'profiled nmethods'":     "CodeHeap'profilednmethods'",
+			"CodeHeap 'non-profiled nmethods'": "CodeHeap'non-profilednmethods'",
+		},
+	},
+	"g1": {
+		memoryPools: map[string]string{
+			"G1 Eden Space":     "G1EdenSpace",
+			"G1 Survivor Space": "G1SurvivorSpace",
+			"G1 Old Gen":        "G1OldGen",
+		},
+		garbageCollectors: map[string]string{
+			"G1 Young Generation": "G1 Young Generation",
+			"G1 Old Generation":   "G1 Old Generation",
+			"G1 Concurrent GC":    "G1 Concurrent GC",
+		},
+	},
+	"parallel": {
+		memoryPools: map[string]string{
+			"PS Eden Space":     "PSEdenSpace",
+			"PS Survivor Space": "PSSurvivorSpace",
+			"PS Old Gen":        "PSOldGen",
+		},
+		garbageCollectors: map[string]string{
+			"PS Scavenge":  "PS Scavenge",
+			"PS MarkSweep": "PS MarkSweep",
+		},
+	},
+	"cms": {
+		memoryPools: map[string]string{
+			"Par Eden Space":     "ParEdenSpace",
+			"Par Survivor Space": "ParSurvivorSpace",
+			"CMS Old Gen":        "CMSOldGen",
+		},
+		garbageCollectors: map[string]string{
+			"ParNew":              "ParNew",
+			"ConcurrentMarkSweep": "ConcurrentMarkSweep",
+		},
+	},
+	"serial": {
+		memoryPools: map[string]string{
+			"Eden Space":     "EdenSpace",
+			"Survivor Space": "SurvivorSpace",
+			"Tenured Gen":    "TenuredGen",
+		},
+		garbageCollectors: map[string]string{
+			"Copy":             "Copy",
+			"MarkSweepCompact": "MarkSweepCompact",
+		},
+	},
+	"zgc": {
+		memoryPools: map[string]string{
+			"ZHeap":                "ZHeap",
+			"ZGC Young Generation": "ZGCYoungGeneration",
+			"ZGC Old Generation":   "ZGCOldGeneration",
+		},
+		garbageCollectors: map[string]string{
+			"ZGC":              "ZGC",
+			"ZGC Cycles":       "ZGC Cycles",
+			"ZGC Pauses":       "ZGC Pauses",
+			"ZGC Minor Cycles": "ZGC Minor Cycles",
+			"ZGC Minor Pauses": "ZGC Minor Pauses",
+			"ZGC Major Cycles": "ZGC Major Cycles",
+			"ZGC Major Pauses": "ZGC Major Pauses",
+		},
+	},
+	"shenandoah": {
+		memoryPools: map[string]string{
+			"Shenandoah": "Shenandoah",
+		},
+		garbageCollectors: map[string]string{
+			"Shenandoah Cycles": "Shenandoah Cycles",
+			"Shenandoah Pauses": "Shenandoah Pauses",
+		},
+	},
+	"openj9": {
+		memoryPools: map[string]string{
+			"nursery-allocate":               "nursery-allocate",
+			"nursery-survivor":               "nursery-survivor",
+			"tenured":                        "tenured",
+			"tenured-LOA":                    "tenured-LOA",
+			"tenured-SOA":                    "tenured-SOA",
+			"balanced-eden":                  "balanced-eden",
+			"balanced-reserved":              "balanced-reserved",
+			"balanced-old":                   "balanced-old",
+			"JIT code cache":                 "JITcodecache",
+			"JIT data cache":                 "JITdatacache",
+			"class storage":                  "classstorage",
+			"miscellaneous non-heap storage": "miscellaneousnon-heapstorage",
+		},
+		garbageCollectors: map[string]string{
+			"scavenge":               "scavenge",
+			"global":                 "global",
+			"partial gc":             "partial gc",
+			"global garbage collect": "global garbage collect",
+		},
+	},
+}
+
+func builtinProfileNames() []string {
+	names := make([]string, 0, len(builtinNameProfiles))
+	for name := range builtinNameProfiles {
+		if name != commonNameProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nameMappingFile 是 file 配置项指向的映射文件格式
+type nameMappingFile struct {
+	MemoryPools       map[string]string `yaml:"memory_pools"`
+	GarbageCollectors map[string]string `yaml:"garbage_collectors"`
+}
+
+// nameMapper 将内存池和垃圾收集器名称映射为后端名称，并上报无法映射的名称
+type nameMapper struct {
+	memoryPools       map[string]string
+	garbageCollectors map[string]string
+	logger            *zap.Logger
+	telemetry         *exporterTelemetry
+	// 已记录过日志的未映射名称，避免每个批次重复打印
+	reported sync.Map
+}
+
+func newNameMapper(cfg NameMappingConfig, logger *zap.Logger, telemetry *exporterTelemetry) (*nameMapper, error) {
+	m := &nameMapper{
+		memoryPools:       make(map[string]string),
+		garbageCollectors: make(map[string]string),
+		logger:            logger,
+		telemetry:         telemetry,
+	}
+	m.add(builtinNameProfiles[commonNameProfile].memoryPools, builtinNameProfiles[commonNameProfile].garbageCollectors)
+	for _, name := range cfg.Profiles {
+		profile, ok := builtinNameProfiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown name mapping profile %q", name)
+		}
+		m.add(profile.memoryPools, profile.garbageCollectors)
+	}
+	if cfg.File != "" {
+		content, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read name mapping file: %w", err)
+		}
+		var file nameMappingFile
+		if err = yaml.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse name mapping file %s: %w", cfg.File, err)
+		}
+		m.add(file.MemoryPools, file.GarbageCollectors)
+	}
+	m.add(cfg.MemoryPools, cfg.GarbageCollectors)
+	return m, nil
+}
+
+func (m *nameMapper) add(memoryPools, garbageCollectors map[string]string) {
+	for k, v := range memoryPools {
+		m.memoryPools[k] = v
+	}
+	for k, v := range garbageCollectors {
+		m.garbageCollectors[k] = v
+	}
+}
+
+func (m *nameMapper) memoryPool(name string) string {
+	return m.lookup(m.memoryPools, "memory_pool", name)
+}
+
+func (m *nameMapper) garbageCollector(name string) string {
+	return m.lookup(m.garbageCollectors, "garbage_collector", name)
+}
+
+func (m *nameMapper) lookup(mapping map[string]string, kind string, name string) string {
+	if mapped, ok := mapping[name]; ok && mapped != "" {
+		return mapped
+	}
+	// 如果映射不到，使用原始名称作为默认值
+	m.telemetry.recordUnmappedName(kind)
+	if _, loaded := m.reported.LoadOrStore(kind+"/"+name, struct{}{}); !loaded {
+		m.logger.Info("No name mapping found, using raw name",
+			zap.String("kind", kind), zap.String("name", name))
+	}
+	return name
+}
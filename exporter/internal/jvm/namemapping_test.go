@@ -0,0 +1,220 @@
+package jvm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newTestTelemetry 返回记录到 reader 的遥测设置
+func newTestTelemetry(t *testing.T) (component.TelemetrySettings, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { require.NoError(t, provider.Shutdown(context.Background())) })
+	return component.TelemetrySettings{Logger: zap.NewNop(), MeterProvider: provider}, reader
+}
+
+// counterValue 返回计数器在带有 attr 属性的序列上的值
+func counterValue(t *testing.T, reader *sdkmetric.ManualReader, name string, attr attribute.KeyValue) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, ok := dp.Attributes.Value(attr.Key); ok && v == attr.Value {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func newTestNameMapper(t *testing.T, cfg NameMappingConfig) *nameMapper {
+	telemetry, err := newExporterTelemetry(component.TelemetrySettings{})
+	require.NoError(t, err)
+	mapper, err := newNameMapper(cfg, zap.NewNop(), telemetry)
+	require.NoError(t, err)
+	return mapper
+}
+
+func TestNameMappingConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     NameMappingConfig
+		wantErr string
+	}{
+		{name: "default", cfg: NewDefaultNameMappingConfig()},
+		{name: "no profiles", cfg: NameMappingConfig{}},
+		{name: "unknown profile", cfg: NameMappingConfig{Profiles: []string{"g1", "epsilon"}}, wantErr: `unknown name mapping profile "epsilon"`},
+		{name: "common is not a profile", cfg: NameMappingConfig{Profiles: []string{"common"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNameMapperProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []string
+		pools    map[string]string
+		gcs      map[string]string
+	}{
+		{
+			name:     "g1",
+			profiles: []string{"g1"},
+			pools:    map[string]string{"G1 Eden Space": "G1EdenSpace", "G1 Old Gen": "G1OldGen"},
+			gcs:      map[string]string{"G1 Young Generation": "G1 Young Generation"},
+		},
+		{
+			name:     "zgc",
+			profiles: []string{"zgc"},
+			pools:    map[string]string{"ZHeap": "ZHeap", "ZGC Young Generation": "ZGCYoungGeneration", "ZGC Old Generation": "ZGCOldGeneration"},
+			gcs:      map[string]string{"ZGC Cycles": "ZGC Cycles", "ZGC Minor Pauses": "ZGC Minor Pauses"},
+		},
+		{
+			name:     "shenandoah",
+			profiles: []string{"shenandoah"},
+			pools:    map[string]string{"Shenandoah": "Shenandoah"},
+			gcs:      map[string]string{"Shenandoah Cycles": "Shenandoah Cycles", "Shenandoah Pauses": "Shenandoah Pauses"},
+		},
+		{
+			name:     "parallel",
+			profiles: []string{"parallel"},
+			pools:    map[string]string{"PS Eden Space": "PSEdenSpace", "PS Survivor Space": "PSSurvivorSpace", "PS Old Gen": "PSOldGen"},
+			gcs:      map[string]string{"PS Scavenge": "PS Scavenge", "PS MarkSweep": "PS MarkSweep"},
+		},
+		{
+			name:     "cms",
+			profiles: []string{"cms"},
+			pools:    map[string]string{"Par Eden Space": "ParEdenSpace", "CMS Old Gen": "CMSOldGen"},
+			gcs:      map[string]string{"ParNew": "ParNew", "ConcurrentMarkSweep": "ConcurrentMarkSweep"},
+		},
+		{
+			name:     "serial",
+			profiles: []string{"serial"},
+			pools:    map[string]string{"Eden Space": "EdenSpace", "Tenured Gen": "TenuredGen"},
+			gcs:      map[string]string{"Copy": "Copy", "MarkSweepCompact": "MarkSweepCompact"},
+		},
+		{
+			name:     "openj9",
+			profiles: []string{"openj9"},
+			pools:    map[string]string{"nursery-allocate": "nursery-allocate", "JIT code cache": "JITcodecache", "class storage": "classstorage"},
+			gcs:      map[string]string{"scavenge": "scavenge", "global garbage collect": "global garbage collect"},
+		},
+		{
+			name:     "common pools are always loaded",
+			profiles: []string{"zgc"},
+			pools: map[string]string{
+				"Metaspace":                    "Metaspace",
+				"Code Cache":                   "CodeCache",
+				"CodeHeap 'profiled nmethods'": "CodeHeap'profilednmethods'",
+			},
+		},
+		{
+			name:     "names of other profiles are unchanged",
+			profiles: []string{"zgc"},
+			pools:    map[string]string{"G1 Eden Space": "G1 Eden Space"},
+			gcs:      map[string]string{"G1 Young Generation": "G1 Young Generation"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := newTestNameMapper(t, NameMappingConfig{Profiles: tt.profiles})
+			for name, want := range tt.pools {
+				assert.Equal(t, want, mapper.memoryPool(name), name)
+			}
+			for name, want := range tt.gcs {
+				assert.Equal(t, want, mapper.garbageCollector(name), name)
+			}
+		})
+	}
+}
+
+func TestNameMapperFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "names.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+memory_pools:
+  G1 Eden Space: Eden
+  Custom Pool: CustomPool
+garbage_collectors:
+  G1 Young Generation: Young
+`), 0o600))
+
+	// 映射文件覆盖内置配置，内联配置覆盖映射文件
+	mapper := newTestNameMapper(t, NameMappingConfig{
+		Profiles:    []string{"g1"},
+		File:        file,
+		MemoryPools: map[string]string{"Custom Pool": "Inline"},
+	})
+	assert.Equal(t, "Eden", mapper.memoryPool("G1 Eden Space"))
+	assert.Equal(t, "G1OldGen", mapper.memoryPool("G1 Old Gen"))
+	assert.Equal(t, "Inline", mapper.memoryPool("Custom Pool"))
+	assert.Equal(t, "Young", mapper.garbageCollector("G1 Young Generation"))
+	assert.Equal(t, "G1 Old Generation", mapper.garbageCollector("G1 Old Generation"))
+}
+
+func TestNewNameMapperErrors(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("memory_pools: [G1 Eden Space]"), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     NameMappingConfig
+		wantErr string
+	}{
+		{name: "unknown profile", cfg: NameMappingConfig{Profiles: []string{"epsilon"}}, wantErr: `unknown name mapping profile "epsilon"`},
+		{name: "missing file", cfg: NameMappingConfig{File: filepath.Join(t.TempDir(), "missing.yaml")}, wantErr: "failed to read name mapping file"},
+		{name: "invalid file", cfg: NameMappingConfig{File: invalid}, wantErr: "failed to parse name mapping file " + invalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry, err := newExporterTelemetry(component.TelemetrySettings{})
+			require.NoError(t, err)
+			_, err = newNameMapper(tt.cfg, zap.NewNop(), telemetry)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNameMapperUnmapped(t *testing.T) {
+	set, reader := newTestTelemetry(t)
+	core, logs := observer.New(zap.InfoLevel)
+	telemetry, err := newExporterTelemetry(set)
+	require.NoError(t, err)
+	mapper, err := newNameMapper(NameMappingConfig{Profiles: []string{"g1"}}, zap.New(core), telemetry)
+	require.NoError(t, err)
+
+	assert.Equal(t, "G1EdenSpace", mapper.memoryPool("G1 Eden Space"))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "Epsilon Heap", mapper.memoryPool("Epsilon Heap"))
+	}
+	assert.Equal(t, "Epsilon", mapper.garbageCollector("Epsilon"))
+
+	assert.Equal(t, int64(3), counterValue(t, reader, "exporter_jvm_unmapped_names", attribute.String("kind", "memory_pool")))
+	assert.Equal(t, int64(1), counterValue(t, reader, "exporter_jvm_unmapped_names", attribute.String("kind", "garbage_collector")))
+	// 每个未映射的名称只记录一次日志
+	assert.Equal(t, 2, logs.FilterMessage("No name mapping found, using raw name").Len())
+}
@@ -0,0 +1,178 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOfflineMaxAgents = 10000
+
+	// 离线消息的 statusReasons
+	offlineStatusReason = "offline"
+)
+
+// OfflineConfig defines how JVMs that stop reporting are detected and reported offline.
+type OfflineConfig struct {
+	// Timeout is how long an agentId may go without metrics before a final message with the
+	// offline status is sent for it. Detection runs every half Timeout. 0 disables detection.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// MaxAgents is the maximum number of tracked agentIds. When it is exceeded the agent seen
+	// least recently is no longer tracked and is never reported offline.
+	MaxAgents int `mapstructure:"max_agents"`
+}
+
+func NewDefaultOfflineConfig() OfflineConfig {
+	return OfflineConfig{
+		MaxAgents: defaultOfflineMaxAgents,
+	}
+}
+
+// Validate checks the offline detection configuration.
+func (cfg *OfflineConfig) Validate() error {
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if cfg.Timeout > 0 && cfg.MaxAgents <= 0 {
+		return errors.New("max_agents must be positive")
+	}
+	return nil
+}
+
+// OfflineAgent 保存生成离线消息所需的 JVM 信息
+type OfflineAgent struct {
+	// Key 为 ResourceKey，用于清理 JVM 状态
+	Key          string
+	AgentID      string
+	MultiAgentID string
+	AppName      string
+	Pid          string
+	Version      string
+	Docker       bool
+	MasterIP     string
+	LastSeen     time.Time
+}
+
+// OfflineTracker 按 agentId 记录最后上报时间，并周期性地通知超时的 JVM
+type OfflineTracker struct {
+	cfg    OfflineConfig
+	notify func(context.Context, []OfflineAgent) error
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	agents map[string]*OfflineAgent
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewOfflineTracker(cfg OfflineConfig, notify func(context.Context, []OfflineAgent) error, logger *zap.Logger) *OfflineTracker {
+	return &OfflineTracker{
+		cfg:    cfg,
+		notify: notify,
+		logger: logger,
+		agents: make(map[string]*OfflineAgent),
+	}
+}
+
+// Start 启动检测离线 JVM 的后台循环
+func (t *OfflineTracker) Start() {
+	// Shutdown 会清空 t.stop，后台循环使用局部变量
+	stop := make(chan struct{})
+	t.stop = stop
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(t.cfg.Timeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.check(context.Background(), time.Now())
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown 停止后台循环。collector 停止时 JVM 仍可能在运行，因此不发送离线消息
+func (t *OfflineTracker) Shutdown(ctx context.Context) error {
+	if t.stop == nil {
+		return nil
+	}
+	// 先清空 stop，等待超时后再次调用 Shutdown 不会重复关闭
+	close(t.stop)
+	t.stop = nil
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// seen 记录 JVM 的最新上报，超过 MaxAgents 时不再跟踪最久未上报的 agent
+func (t *OfflineTracker) seen(agent OfflineAgent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.agents[agent.AgentID] = &agent
+	if len(t.agents) <= t.cfg.MaxAgents {
+		return
+	}
+	var oldest *OfflineAgent
+	for _, tracked := range t.agents {
+		if oldest == nil || tracked.LastSeen.Before(oldest.LastSeen) {
+			oldest = tracked
+		}
+	}
+	delete(t.agents, oldest.AgentID)
+	t.logger.Debug("Too many tracked JVMs, no longer tracking the least recently seen one",
+		zap.String("agentId", oldest.AgentID), zap.Int("max_agents", t.cfg.MaxAgents))
+}
+
+// expire 移除并返回超过 Timeout 未上报的 agent，按 agentId 排序
+func (t *OfflineTracker) expire(now time.Time) []OfflineAgent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var expired []OfflineAgent
+	for agentID, agent := range t.agents {
+		if now.Sub(agent.LastSeen) > t.cfg.Timeout {
+			expired = append(expired, *agent)
+			delete(t.agents, agentID)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].AgentID < expired[j].AgentID })
+	return expired
+}
+
+// restore 将通知失败的 agent 放回，期间重新上报过的 agent 除外
+func (t *OfflineTracker) restore(agents []OfflineAgent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, agent := range agents {
+		if _, ok := t.agents[agent.AgentID]; !ok && len(t.agents) < t.cfg.MaxAgents {
+			t.agents[agent.AgentID] = &agent
+		}
+	}
+}
+
+func (t *OfflineTracker) check(ctx context.Context, now time.Time) {
+	expired := t.expire(now)
+	if len(expired) == 0 {
+		return
+	}
+	if err := t.notify(ctx, expired); err != nil {
+		t.logger.Warn("Failed to report offline JVMs, retrying on the next check",
+			zap.Int("jvms", len(expired)), zap.Error(err))
+		t.restore(expired)
+		return
+	}
+	t.logger.Info("Reported offline JVMs", zap.Int("jvms", len(expired)))
+}
@@ -0,0 +1,128 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOfflineConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     OfflineConfig
+		wantErr string
+	}{
+		{name: "default", cfg: NewDefaultOfflineConfig()},
+		{name: "disabled without max agents", cfg: OfflineConfig{}},
+		{name: "enabled", cfg: OfflineConfig{Timeout: time.Minute, MaxAgents: 10}},
+		{name: "negative timeout", cfg: OfflineConfig{Timeout: -time.Minute, MaxAgents: 10}, wantErr: "timeout must not be negative"},
+		{name: "enabled without max agents", cfg: OfflineConfig{Timeout: time.Minute}, wantErr: "max_agents must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOfflineTrackerCheck(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	type seen struct {
+		agentID string
+		at      time.Duration
+	}
+	type check struct {
+		at        time.Duration
+		notifyErr error
+		want      []string
+	}
+	tests := []struct {
+		name      string
+		maxAgents int
+		seen      []seen
+		checks    []check
+	}{
+		{
+			name: "agents past the timeout are reported once in agentId order",
+			seen: []seen{{agentID: "b"}, {agentID: "a"}, {agentID: "c", at: 90 * time.Second}},
+			checks: []check{
+				{at: 30 * time.Second},
+				{at: 2 * time.Minute, want: []string{"a", "b"}},
+				{at: 3 * time.Minute, want: []string{"c"}},
+				{at: 4 * time.Minute},
+			},
+		},
+		{
+			name: "reporting again resets the timeout",
+			seen: []seen{{agentID: "a"}, {agentID: "a", at: 50 * time.Second}},
+			checks: []check{
+				{at: 90 * time.Second},
+				{at: 2 * time.Minute, want: []string{"a"}},
+			},
+		},
+		{
+			name: "failed notifications are retried",
+			seen: []seen{{agentID: "a"}},
+			checks: []check{
+				{at: 2 * time.Minute, notifyErr: errors.New("backend unavailable"), want: []string{"a"}},
+				{at: 3 * time.Minute, want: []string{"a"}},
+				{at: 4 * time.Minute},
+			},
+		},
+		{
+			name:      "least recently seen agent is dropped above max agents",
+			maxAgents: 2,
+			seen:      []seen{{agentID: "a"}, {agentID: "b", at: time.Second}, {agentID: "c", at: 2 * time.Second}},
+			checks: []check{
+				{at: 2 * time.Minute, want: []string{"b", "c"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxAgents := tt.maxAgents
+			if maxAgents == 0 {
+				maxAgents = defaultOfflineMaxAgents
+			}
+			var notified []string
+			var notifyErr error
+			notify := func(_ context.Context, agents []OfflineAgent) error {
+				for _, agent := range agents {
+					notified = append(notified, agent.AgentID)
+				}
+				return notifyErr
+			}
+			tracker := NewOfflineTracker(OfflineConfig{Timeout: time.Minute, MaxAgents: maxAgents}, notify, zap.NewNop())
+			for _, s := range tt.seen {
+				tracker.seen(OfflineAgent{AgentID: s.agentID, LastSeen: start.Add(s.at)})
+			}
+			for _, c := range tt.checks {
+				notified, notifyErr = nil, c.notifyErr
+				tracker.check(context.Background(), start.Add(c.at))
+				assert.Equal(t, c.want, notified, "check at %s", c.at)
+			}
+		})
+	}
+}
+
+func TestOfflineTrackerShutdown(t *testing.T) {
+	tracker := NewOfflineTracker(OfflineConfig{Timeout: time.Hour, MaxAgents: 10}, func(context.Context, []OfflineAgent) error {
+		return nil
+	}, zap.NewNop())
+	// 未启动时 Shutdown 直接返回
+	require.NoError(t, tracker.Shutdown(context.Background()))
+	tracker.Start()
+	require.NoError(t, tracker.Shutdown(context.Background()))
+	// 再次调用不会重复关闭
+	require.NoError(t, tracker.Shutdown(context.Background()))
+}
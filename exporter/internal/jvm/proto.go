@@ -0,0 +1,116 @@
+package jvm
+
+import "github.com/Liuxiaoxxz/third-party/grpc/metrics"
+
+// ManagementMessageToProto 将 JManagementMessage 转换为 gRPC 消息，线程详情直接使用 threadDetails
+func ManagementMessageToProto(message *JManagementMessage, threadDetails []ThreadDetail) *metrics.ExportMetricsServiceRequest {
+	data := &metrics.ExportMetricsServiceRequest{
+		BufferPool: &metrics.BufferPool{
+			Mapped: &metrics.BufferPool_Mapped{
+				Count:    message.BufferPool.Mapped.Count,
+				Used:     message.BufferPool.Mapped.Used,
+				Capacity: message.BufferPool.Mapped.Capacity,
+			},
+			Direct: &metrics.BufferPool_Direct{
+				Count:    message.BufferPool.Direct.Count,
+				Used:     message.BufferPool.Direct.Used,
+				Capacity: message.BufferPool.Direct.Capacity,
+			},
+		},
+		AgentId:      message.AgentId,
+		CreationTime: message.CreationTime,
+		AppName:      message.AppName,
+		AppStartTime: message.AppStartTime,
+		Cpu: &metrics.CPU{
+			ProcessCpu:    message.CPU.ProcessCpu,
+			AvgSystemCpu:  message.CPU.AvgSystemCpu,
+			SystemCpu:     message.CPU.SystemCpu,
+			AvgProcessCpu: message.CPU.AvgProcessCpu,
+		},
+		Pid: message.Pid,
+		Thread: &metrics.Thread{
+			ThreadCount:             message.Thread.ThreadCount,
+			TotalStartedThreadCount: message.Thread.TotalStartedThreadCount,
+			PeakThreadCount:         message.Thread.PeakThreadCount,
+			DeamonThreadCount:       message.Thread.DeamonThreadCount,
+			StateCounts:             message.Thread.StateCounts,
+		},
+		MemoryPool: &metrics.MemoryPool{
+			MemoryUsages: make(map[string]*metrics.MemoryUsage, len(message.MemoryPool.MemoryUsages)),
+		},
+		Version: message.Version,
+		Docker:  message.Docker,
+		GarbageCollector: &metrics.GarbageCollector{
+			GarbageCollectors: make(map[string]*metrics.GarbageCollectorInfo, len(message.GarbageCollector.GarbageCollectors)),
+		},
+		MultiAgentId:              message.MultiAgentId,
+		DatabaseConnectionMessage: &metrics.DatabaseConnectionMessage{},
+		Status:                    int32(message.Status),
+		StatusReasons:             message.StatusReasons,
+		ClassLoading: &metrics.ClassLoading{
+			LoadedClassCount:      message.ClassLoading.LoadedClassCount,
+			TotalLoadedClassCount: message.ClassLoading.TotalLoadedClassCount,
+			UnloadedClassCount:    message.ClassLoading.UnloadedClassCount,
+		},
+		Compilation: &metrics.Compilation{
+			Name:                 message.Compilation.Name,
+			TotalCompilationTime: message.Compilation.TotalCompilationTime,
+		},
+	}
+	if len(threadDetails) > 0 {
+		threadInfos := &metrics.ThreadInfos{LockNames: lockNames(threadDetails)}
+		for _, detail := range threadDetails {
+			threadInfos.ThreadInfo = append(threadInfos.ThreadInfo, &metrics.ThreadInfo{
+				ThreadId:      detail.id,
+				ThreadName:    detail.name,
+				ThreadState:   detail.state,
+				LockName:      detail.lockName,
+				LockOwnerId:   detail.lockOwnerID,
+				LockOwnerName: detail.lockOwnerName,
+				Deadlocked:    detail.deadlocked,
+				StackTrace:    detail.stackTrace,
+			})
+		}
+		data.Thread.ThreadInfos = threadInfos
+	}
+	for name, usage := range message.MemoryPool.MemoryUsages {
+		data.MemoryPool.MemoryUsages[name] = &metrics.MemoryUsage{
+			Init:      usage.Init,
+			Committed: usage.Committed,
+			Max:       usage.Max,
+			Used:      usage.Used,
+		}
+	}
+	for name, collector := range message.GarbageCollector.GarbageCollectors {
+		data.GarbageCollector.GarbageCollectors[name] = &metrics.GarbageCollectorInfo{
+			Valid:           collector.Valid,
+			CollectionTime:  int32(collector.CollectionTime),
+			MemoryPoolNames: collector.MemoryPoolNames,
+			CollectionCount: collector.CollectionCount,
+			Name:            collector.Name,
+		}
+	}
+	databaseConnections := data.DatabaseConnectionMessage
+	for _, connection := range message.DatabaseConnectionMessage.DatabaseConnectionMessageArray {
+		databaseConnections.DatabaseConnectionMessageArray = append(databaseConnections.DatabaseConnectionMessageArray, &metrics.DatabaseConnection{
+			PoolName:        connection.PoolName,
+			Idle:            connection.Idle,
+			Used:            connection.Used,
+			Max:             connection.Max,
+			PendingRequests: connection.PendingRequests,
+			WaitTime:        connection.WaitTime,
+			UseTime:         connection.UseTime,
+		})
+	}
+	for _, suspicion := range message.DatabaseConnectionMessage.LeakSuspicious {
+		databaseConnections.LeakSuspicious = append(databaseConnections.LeakSuspicious, &metrics.LeakSuspicion{
+			PoolName:  suspicion.PoolName,
+			Reason:    suspicion.Reason,
+			Intervals: int32(suspicion.Intervals),
+			Used:      suspicion.Used,
+			Max:       suspicion.Max,
+			WaitTime:  suspicion.WaitTime,
+		})
+	}
+	return data
+}
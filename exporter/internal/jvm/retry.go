@@ -0,0 +1,83 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// throttleRetry 包装 exporterhelper 的限流错误，exporterhelper 的限流类型不可导出，
+// Retry 通过该类型读取延迟
+type throttleRetry struct {
+	err   error
+	delay time.Duration
+}
+
+func (t throttleRetry) Error() string {
+	return t.err.Error()
+}
+
+func (t throttleRetry) Unwrap() error {
+	return t.err
+}
+
+// NewThrottleRetry creates an error that asks for the request to be retried after delay. It wraps
+// exporterhelper.NewThrottleRetry, so both the sending queue of the exporter and Retry honor the
+// delay.
+func NewThrottleRetry(err error, delay time.Duration) error {
+	return throttleRetry{
+		err:   exporterhelper.NewThrottleRetry(err, delay),
+		delay: delay,
+	}
+}
+
+// Retry calls send until it succeeds, fails with a permanent error, MaxElapsedTime passes or ctx
+// is done, waiting between attempts as configured by retry_on_failure or at least as long as an
+// error created by NewThrottleRetry asks. Assembled snapshots do not pass through the sending
+// queue of the exporter, so they are retried with this instead.
+func Retry(ctx context.Context, cfg configretry.BackOffConfig, send func() error) error {
+	err := send()
+	if err == nil || !cfg.Enabled || consumererror.IsPermanent(err) {
+		return err
+	}
+	// 与 exporterhelper 的重试使用相同的退避参数
+	expBackoff := backoff.ExponentialBackOff{
+		InitialInterval:     cfg.InitialInterval,
+		RandomizationFactor: cfg.RandomizationFactor,
+		Multiplier:          cfg.Multiplier,
+		MaxInterval:         cfg.MaxInterval,
+		MaxElapsedTime:      cfg.MaxElapsedTime,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	expBackoff.Reset()
+	for {
+		delay := expBackoff.NextBackOff()
+		if delay == backoff.Stop {
+			return err
+		}
+		// 与 exporterhelper 相同，后端要求的等待时间长于退避时间时按后端要求等待
+		throttle := throttleRetry{}
+		if errors.As(err, &throttle) {
+			delay = max(delay, throttle.delay)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		if err = send(); err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+	}
+}
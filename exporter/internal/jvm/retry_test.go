@@ -0,0 +1,75 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestRetry(t *testing.T) {
+	errUnavailable := errors.New("backend unavailable")
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   string
+		minWait   time.Duration
+	}{
+		{name: "success", wantCalls: 1},
+		{name: "retryable error", errs: []error{errUnavailable, errUnavailable}, wantCalls: 3},
+		{
+			name:      "permanent error",
+			errs:      []error{consumererror.NewPermanent(errUnavailable)},
+			wantCalls: 1,
+			wantErr:   "Permanent error: backend unavailable",
+		},
+		{
+			name:      "throttled",
+			errs:      []error{NewThrottleRetry(errUnavailable, 50*time.Millisecond)},
+			wantCalls: 2,
+			minWait:   50 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configretry.NewDefaultBackOffConfig()
+			cfg.InitialInterval = time.Millisecond
+			cfg.MaxInterval = time.Millisecond
+			calls := 0
+			start := time.Now()
+			err := Retry(context.Background(), cfg, func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.GreaterOrEqual(t, time.Since(start), tt.minWait)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRetryThrottleBeyondDeadline(t *testing.T) {
+	cfg := configretry.NewDefaultBackOffConfig()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	calls := 0
+	err := Retry(ctx, cfg, func() error {
+		calls++
+		return NewThrottleRetry(errors.New("backend unavailable"), time.Minute)
+	})
+	// 等待时间超过截止时间时不再等待
+	assert.Equal(t, 1, calls)
+	assert.EqualError(t, err, "Throttle (1m0s), error: backend unavailable")
+}
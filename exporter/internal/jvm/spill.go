@@ -0,0 +1,315 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSpillMaxBytes       = 256 << 20
+	defaultSpillMaxAge         = 24 * time.Hour
+	defaultSpillReplayInterval = 30 * time.Second
+
+	spillTempSuffix = ".tmp"
+
+	// 丢弃原因
+	spillDropSize     = "size"
+	spillDropAge      = "age"
+	spillDropRejected = "rejected"
+	spillDropCorrupt  = "corrupt"
+)
+
+// SpillConfig defines how converted payloads that cannot be sent are persisted and replayed.
+type SpillConfig struct {
+	// Directory is where payloads are stored while the backend cannot be reached. Each exporter
+	// and signal uses its own sub-directory. Empty disables spilling. Only converted payloads are
+	// spilled: JVM metrics and offline events, and with the jvmhttp exporter transactions and logs
+	// when conversion.traces or conversion.logs is enabled. Signals forwarded as OTLP are only
+	// retried by the sending queue.
+	Directory string `mapstructure:"directory"`
+
+	// MaxBytes is the maximum total size of stored payloads. The oldest payloads are dropped
+	// when it is exceeded.
+	MaxBytes int64 `mapstructure:"max_bytes"`
+
+	// MaxAge is how long a payload is kept. Older payloads are dropped instead of replayed.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// ReplayInterval is how often sending the stored payloads is retried.
+	ReplayInterval time.Duration `mapstructure:"replay_interval"`
+}
+
+func NewDefaultSpillConfig() SpillConfig {
+	return SpillConfig{
+		MaxBytes:       defaultSpillMaxBytes,
+		MaxAge:         defaultSpillMaxAge,
+		ReplayInterval: defaultSpillReplayInterval,
+	}
+}
+
+// Validate checks the spill configuration.
+func (cfg *SpillConfig) Validate() error {
+	if cfg.Directory == "" {
+		return nil
+	}
+	if cfg.MaxBytes <= 0 {
+		return errors.New("max_bytes must be positive")
+	}
+	if cfg.MaxAge <= 0 {
+		return errors.New("max_age must be positive")
+	}
+	if cfg.ReplayInterval <= 0 {
+		return errors.New("replay_interval must be positive")
+	}
+	return nil
+}
+
+// spillFile 是目录中的一个待回放请求体，文件名为 <序号>.<kind>，序号为写入时间的纳秒数
+type spillFile struct {
+	name    string
+	kind    string
+	size    int64
+	written time.Time
+}
+
+// SpillQueue 将发送失败的请求体按顺序保存到磁盘，并在后端恢复后按顺序回放
+type SpillQueue struct {
+	cfg       SpillConfig
+	dir       string
+	signal    string
+	send      func(context.Context, string, []byte) error
+	telemetry *exporterTelemetry
+	logger    *zap.Logger
+
+	// mu 保护 files、bytes 和 last
+	mu    sync.Mutex
+	files []spillFile
+	bytes int64
+	last  int64
+
+	// replayMu 保证同一时间只有一个回放
+	replayMu sync.Mutex
+	trigger  chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewSpillQueue(cfg SpillConfig, id component.ID, signal string, send func(context.Context, string, []byte) error, set component.TelemetrySettings) (*SpillQueue, error) {
+	telemetry, err := newExporterTelemetry(set)
+	if err != nil {
+		return nil, err
+	}
+	return &SpillQueue{
+		cfg:       cfg,
+		dir:       filepath.Join(cfg.Directory, id.Type().String(), id.Name(), signal),
+		signal:    signal,
+		send:      send,
+		telemetry: telemetry,
+		logger:    set.Logger,
+		trigger:   make(chan struct{}, 1),
+	}, nil
+}
+
+// Start 加载目录中上次未回放的请求体并启动回放循环
+func (q *SpillQueue) Start() error {
+	if err := q.load(); err != nil {
+		return fmt.Errorf("failed to load spilled payloads from %s: %w", q.dir, err)
+	}
+	// Shutdown 会清空 q.stop，后台循环使用局部变量
+	stop := make(chan struct{})
+	q.stop = stop
+	q.done = make(chan struct{})
+	go func() {
+		defer close(q.done)
+		ticker := time.NewTicker(q.cfg.ReplayInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-q.trigger:
+			case <-stop:
+				return
+			}
+			if err := q.replay(context.Background(), time.Now()); err != nil {
+				q.logger.Debug("Backend still unavailable, keeping spilled payloads",
+					zap.String("signal", q.signal), zap.Int("payloads", q.Pending()), zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown 停止回放循环，未回放的请求体留在磁盘上，下次启动时继续回放
+func (q *SpillQueue) Shutdown(ctx context.Context) error {
+	if q.stop == nil {
+		return nil
+	}
+	// 先清空 stop，等待超时后再次调用 Shutdown 不会重复关闭
+	close(q.stop)
+	q.stop = nil
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (q *SpillQueue) load() error {
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		// 写入过程中退出留下的临时文件
+		if strings.HasSuffix(name, spillTempSuffix) {
+			_ = os.Remove(filepath.Join(q.dir, name))
+			continue
+		}
+		seq, kind, ok := strings.Cut(name, ".")
+		nanos, err := strconv.ParseInt(seq, 10, 64)
+		if !ok || err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		q.files = append(q.files, spillFile{name: name, kind: kind, size: info.Size(), written: time.Unix(0, nanos)})
+		q.bytes += info.Size()
+		q.last = max(q.last, nanos)
+	}
+	sort.Slice(q.files, func(i, j int) bool { return q.files[i].name < q.files[j].name })
+	q.telemetry.recordSpillQueue(q.signal, int64(len(q.files)), q.bytes)
+	if len(q.files) > 0 {
+		q.logger.Info("Found spilled payloads, replaying them when the backend is available",
+			zap.String("signal", q.signal), zap.Int("payloads", len(q.files)), zap.Int64("bytes", q.bytes))
+	}
+	return nil
+}
+
+// Pending 返回待回放的请求体数量
+func (q *SpillQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.files)
+}
+
+// Put 将请求体追加到队列末尾，超过 MaxBytes 时丢弃最旧的请求体
+func (q *SpillQueue) Put(kind string, payload []byte, now time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// 序号必须递增以保证回放顺序
+	nanos := max(now.UnixNano(), q.last+1)
+	name := fmt.Sprintf("%020d.%s", nanos, kind)
+	path := filepath.Join(q.dir, name)
+	if err := os.WriteFile(path+spillTempSuffix, payload, 0o600); err != nil {
+		return fmt.Errorf("failed to spill payload: %w", err)
+	}
+	if err := os.Rename(path+spillTempSuffix, path); err != nil {
+		_ = os.Remove(path + spillTempSuffix)
+		return fmt.Errorf("failed to spill payload: %w", err)
+	}
+	q.last = nanos
+	size := int64(len(payload))
+	q.files = append(q.files, spillFile{name: name, kind: kind, size: size, written: time.Unix(0, nanos)})
+	q.bytes += size
+	q.telemetry.recordSpillQueue(q.signal, 1, size)
+	for q.bytes > q.cfg.MaxBytes && len(q.files) > 0 {
+		q.removeLocked(spillDropSize)
+	}
+	// 通知回放循环尽快尝试
+	select {
+	case q.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// replay 按写入顺序发送请求体，遇到可重试的错误时停止
+func (q *SpillQueue) replay(ctx context.Context, now time.Time) error {
+	q.replayMu.Lock()
+	defer q.replayMu.Unlock()
+	replayed := 0
+	defer func() {
+		if replayed > 0 {
+			q.logger.Info("Replayed spilled payloads", zap.String("signal", q.signal), zap.Int("payloads", replayed))
+		}
+	}()
+	for {
+		q.mu.Lock()
+		if len(q.files) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		file := q.files[0]
+		if now.Sub(file.written) > q.cfg.MaxAge {
+			q.removeLocked(spillDropAge)
+			q.mu.Unlock()
+			continue
+		}
+		q.mu.Unlock()
+
+		payload, err := os.ReadFile(filepath.Join(q.dir, file.name))
+		if err != nil {
+			q.logger.Warn("Dropping unreadable spilled payload", zap.String("file", file.name), zap.Error(err))
+			q.remove(file.name, spillDropCorrupt)
+			continue
+		}
+		err = q.send(ctx, file.kind, payload)
+		switch {
+		case err == nil:
+			q.remove(file.name, "")
+			replayed++
+		case consumererror.IsPermanent(err):
+			q.logger.Warn("Dropping spilled payload rejected by the backend", zap.String("file", file.name), zap.Error(err))
+			q.remove(file.name, spillDropRejected)
+		default:
+			return err
+		}
+	}
+}
+
+// remove 删除队首的 name，put 可能已经因大小限制删除了它
+func (q *SpillQueue) remove(name string, reason string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.files) > 0 && q.files[0].name == name {
+		q.removeLocked(reason)
+	}
+}
+
+// removeLocked 删除队首的请求体，reason 为空表示已成功回放
+func (q *SpillQueue) removeLocked(reason string) {
+	file := q.files[0]
+	q.files = q.files[1:]
+	q.bytes -= file.size
+	if err := os.Remove(filepath.Join(q.dir, file.name)); err != nil && !os.IsNotExist(err) {
+		q.logger.Warn("Failed to remove spilled payload", zap.String("file", file.name), zap.Error(err))
+	}
+	q.telemetry.recordSpillQueue(q.signal, -1, -file.size)
+	if reason != "" {
+		q.telemetry.recordSpillDrop(q.signal, reason)
+	}
+}
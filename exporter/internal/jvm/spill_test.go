@@ -0,0 +1,176 @@
+package jvm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// spilledPayload 是写入或回放的一个请求体
+type spilledPayload struct {
+	kind    string
+	payload string
+}
+
+func newTestSpillQueue(t *testing.T, cfg SpillConfig, send func(context.Context, string, []byte) error) *SpillQueue {
+	queue, err := NewSpillQueue(cfg, component.MustNewIDWithName("jvmhttp", "test"), "metrics", send, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	require.NoError(t, queue.load())
+	return queue
+}
+
+func TestSpillConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*SpillConfig)
+		wantErr string
+	}{
+		{name: "disabled", modify: func(*SpillConfig) {}},
+		{name: "disabled ignores limits", modify: func(cfg *SpillConfig) { cfg.MaxBytes = 0 }},
+		{name: "enabled", modify: func(cfg *SpillConfig) { cfg.Directory = "/var/lib/otelcol" }},
+		{name: "no max bytes", modify: func(cfg *SpillConfig) { cfg.Directory, cfg.MaxBytes = "/var/lib/otelcol", 0 }, wantErr: "max_bytes must be positive"},
+		{name: "no max age", modify: func(cfg *SpillConfig) { cfg.Directory, cfg.MaxAge = "/var/lib/otelcol", 0 }, wantErr: "max_age must be positive"},
+		{name: "no replay interval", modify: func(cfg *SpillConfig) { cfg.Directory, cfg.ReplayInterval = "/var/lib/otelcol", 0 }, wantErr: "replay_interval must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultSpillConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSpillQueueReplay(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	errUnavailable := errors.New("backend unavailable")
+	tests := []struct {
+		name     string
+		maxBytes int64
+		put      []spilledPayload
+		// sendErr 返回第 n 次发送的结果
+		sendErr     func(n int, payload spilledPayload) error
+		replayAt    time.Duration
+		wantErr     error
+		wantSent    []spilledPayload
+		wantPending int
+	}{
+		{
+			name:     "payloads are replayed in write order",
+			put:      []spilledPayload{{"metrics", "1"}, {"offline", "2"}, {"metrics", "3"}},
+			wantSent: []spilledPayload{{"metrics", "1"}, {"offline", "2"}, {"metrics", "3"}},
+		},
+		{
+			name: "retryable error stops the replay",
+			put:  []spilledPayload{{"metrics", "1"}, {"metrics", "2"}, {"metrics", "3"}},
+			sendErr: func(n int, _ spilledPayload) error {
+				if n == 1 {
+					return errUnavailable
+				}
+				return nil
+			},
+			wantErr:     errUnavailable,
+			wantSent:    []spilledPayload{{"metrics", "1"}, {"metrics", "2"}},
+			wantPending: 2,
+		},
+		{
+			name: "rejected payloads are dropped",
+			put:  []spilledPayload{{"metrics", "1"}, {"metrics", "2"}},
+			sendErr: func(_ int, payload spilledPayload) error {
+				if payload.payload == "1" {
+					return consumererror.NewPermanent(errors.New("bad request"))
+				}
+				return nil
+			},
+			wantSent: []spilledPayload{{"metrics", "1"}, {"metrics", "2"}},
+		},
+		{
+			name:     "oldest payloads are dropped above max bytes",
+			maxBytes: 4,
+			put:      []spilledPayload{{"metrics", "11"}, {"metrics", "22"}, {"metrics", "33"}},
+			wantSent: []spilledPayload{{"metrics", "22"}, {"metrics", "33"}},
+		},
+		{
+			name:     "payloads older than max age are dropped",
+			put:      []spilledPayload{{"metrics", "1"}},
+			replayAt: 2 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := SpillConfig{Directory: t.TempDir(), MaxBytes: 1 << 20, MaxAge: time.Hour, ReplayInterval: time.Minute}
+			if tt.maxBytes > 0 {
+				cfg.MaxBytes = tt.maxBytes
+			}
+			var sent []spilledPayload
+			queue := newTestSpillQueue(t, cfg, func(_ context.Context, kind string, payload []byte) error {
+				p := spilledPayload{kind, string(payload)}
+				sent = append(sent, p)
+				if tt.sendErr != nil {
+					return tt.sendErr(len(sent)-1, p)
+				}
+				return nil
+			})
+			for _, p := range tt.put {
+				// 同一时间写入的请求体也按写入顺序回放
+				require.NoError(t, queue.Put(p.kind, []byte(p.payload), start))
+			}
+			assert.Equal(t, tt.wantErr, queue.replay(context.Background(), start.Add(tt.replayAt)))
+			assert.Equal(t, tt.wantSent, sent)
+			assert.Equal(t, tt.wantPending, queue.Pending())
+			entries, err := os.ReadDir(queue.dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.wantPending)
+		})
+	}
+}
+
+func TestSpillQueueLoad(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	cfg := SpillConfig{Directory: t.TempDir(), MaxBytes: 1 << 20, MaxAge: time.Hour, ReplayInterval: time.Minute}
+	first := newTestSpillQueue(t, cfg, nil)
+	require.NoError(t, first.Put("metrics", []byte("1"), start))
+	require.NoError(t, first.Put("offline", []byte("2"), start))
+	// 写入过程中退出留下的临时文件和无关文件
+	require.NoError(t, os.WriteFile(filepath.Join(first.dir, "00000000000000000003.metrics"+spillTempSuffix), []byte("3"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(first.dir, "README"), []byte("notes"), 0o600))
+
+	var sent []spilledPayload
+	second := newTestSpillQueue(t, cfg, func(_ context.Context, kind string, payload []byte) error {
+		sent = append(sent, spilledPayload{kind, string(payload)})
+		return nil
+	})
+	assert.Equal(t, 2, second.Pending())
+	require.NoError(t, second.Put("metrics", []byte("4"), start))
+	require.NoError(t, second.replay(context.Background(), start))
+	assert.Equal(t, []spilledPayload{{"metrics", "1"}, {"offline", "2"}, {"metrics", "4"}}, sent)
+	assert.NoFileExists(t, filepath.Join(first.dir, "00000000000000000003.metrics"+spillTempSuffix))
+}
+
+func TestSpillQueueShutdown(t *testing.T) {
+	cfg := SpillConfig{Directory: t.TempDir(), MaxBytes: 1 << 20, MaxAge: time.Hour, ReplayInterval: time.Hour}
+	queue, err := NewSpillQueue(cfg, component.MustNewID("jvmhttp"), "metrics", func(context.Context, string, []byte) error {
+		return nil
+	}, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	// 未启动时 Shutdown 直接返回
+	require.NoError(t, queue.Shutdown(context.Background()))
+	require.NoError(t, queue.Start())
+	require.NoError(t, queue.Shutdown(context.Background()))
+	// 再次调用不会重复关闭
+	require.NoError(t, queue.Shutdown(context.Background()))
+}
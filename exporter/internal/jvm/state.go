@@ -0,0 +1,124 @@
+package jvm
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// jvmStateExpiry 超过该时间未上报的 JVM 状态会被清理
+const jvmStateExpiry = time.Hour
+
+// jvmState 保存单个 JVM 跨批次的状态
+type jvmState struct {
+	lastSeen time.Time
+
+	// 用于识别进程重启，seriesStarts 按序列记录累计指标的起始时间
+	pid          string
+	seriesStarts map[string]seriesStart
+
+	cpu     cpuState
+	threads threadState
+	gc      gcState
+	classes classState
+	db      dbState
+	health  healthState
+}
+
+// checkRestart 根据 pid 和累计序列的起始时间判断 JVM 是否重启，重启时清空历史状态。
+// 只有已知序列的起始时间越过该序列上一个数据点的时间才视为重启，新出现的序列
+// （新的 GC 原因、连接池等）以及 Prometheus 按序列调整的起始时间都不会清空状态
+func (s *jvmState) checkRestart(pid string, starts map[string]seriesStart) bool {
+	restarted := s.pid != "" && pid != "" && pid != s.pid
+	for key, series := range starts {
+		if last, ok := s.seriesStarts[key]; ok && series.start > last.start && series.start > last.timestamp {
+			restarted = true
+		}
+	}
+	if restarted {
+		*s = jvmState{lastSeen: s.lastSeen}
+	}
+	if pid != "" {
+		s.pid = pid
+	}
+	if s.seriesStarts == nil {
+		s.seriesStarts = make(map[string]seriesStart)
+	}
+	for key, series := range starts {
+		if series.timestamp >= s.seriesStarts[key].timestamp {
+			s.seriesStarts[key] = series
+		}
+	}
+	return restarted
+}
+
+// jvmStates 按 JVM 标识保存状态，调用方负责加锁
+type jvmStates struct {
+	states map[string]*jvmState
+}
+
+func newJVMStates() *jvmStates {
+	return &jvmStates{
+		states: make(map[string]*jvmState),
+	}
+}
+
+// get 返回 key 对应的状态，不存在时创建
+func (s *jvmStates) get(key string, now time.Time) *jvmState {
+	state, ok := s.states[key]
+	if !ok {
+		state = &jvmState{}
+		s.states[key] = state
+	}
+	state.lastSeen = now
+	return state
+}
+
+// expire 清理长时间未上报的 JVM
+func (s *jvmStates) expire(now time.Time) {
+	for key, state := range s.states {
+		if now.Sub(state.lastSeen) > jvmStateExpiry {
+			delete(s.states, key)
+		}
+	}
+}
+
+// seriesStart 是累计序列的起始时间和最新数据点的时间
+type seriesStart struct {
+	start     pcommon.Timestamp
+	timestamp pcommon.Timestamp
+}
+
+// addSeriesStarts 按指标名和数据点属性记录累计类型数据点的起始时间，同一序列保留最新的数据点
+func addSeriesStarts(starts map[string]seriesStart, metric pmetric.Metric) {
+	add := func(attributes pcommon.Map, start, timestamp pcommon.Timestamp) {
+		if start == 0 {
+			return
+		}
+		key := metric.Name() + "{" + attributesKey(attributes) + "}"
+		if last, ok := starts[key]; !ok || timestamp >= last.timestamp {
+			starts[key] = seriesStart{start: start, timestamp: timestamp}
+		}
+	}
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		sum := metric.Sum()
+		if sum.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+			return
+		}
+		dataPoints := sum.DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			add(dataPoints.At(i).Attributes(), dataPoints.At(i).StartTimestamp(), dataPoints.At(i).Timestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		histogram := metric.Histogram()
+		if histogram.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+			return
+		}
+		dataPoints := histogram.DataPoints()
+		for i := 0; i < dataPoints.Len(); i++ {
+			add(dataPoints.At(i).Attributes(), dataPoints.At(i).StartTimestamp(), dataPoints.At(i).Timestamp())
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package jvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestJVMStateCheckRestart(t *testing.T) {
+	tests := []struct {
+		name       string
+		pid        string
+		starts     map[string]seriesStart
+		next       string
+		nextStarts map[string]seriesStart
+		want       bool
+	}{
+		{
+			name: "first batch",
+			next: "100",
+			want: false,
+		},
+		{
+			name: "same pid",
+			pid:  "100",
+			next: "100",
+			want: false,
+		},
+		{
+			name: "pid changed",
+			pid:  "100",
+			next: "200",
+			want: true,
+		},
+		{
+			name: "pid missing in the batch",
+			pid:  "100",
+			next: "",
+			want: false,
+		},
+		{
+			name:       "start timestamp moved past the previous point",
+			pid:        "100",
+			starts:     map[string]seriesStart{"jvm.cpu.time{}": {start: 10, timestamp: 50}},
+			next:       "100",
+			nextStarts: map[string]seriesStart{"jvm.cpu.time{}": {start: 60, timestamp: 70}},
+			want:       true,
+		},
+		{
+			name:       "same start timestamp",
+			starts:     map[string]seriesStart{"jvm.cpu.time{}": {start: 10, timestamp: 50}},
+			nextStarts: map[string]seriesStart{"jvm.cpu.time{}": {start: 10, timestamp: 70}},
+			want:       false,
+		},
+		{
+			name:       "start timestamp adjusted before the previous point",
+			starts:     map[string]seriesStart{"jvm.cpu.time{}": {start: 10, timestamp: 50}},
+			nextStarts: map[string]seriesStart{"jvm.cpu.time{}": {start: 40, timestamp: 70}},
+			want:       false,
+		},
+		{
+			name:       "new series of a known metric",
+			starts:     map[string]seriesStart{"jvm.gc.duration{jvm.gc.cause=System.gc()}": {start: 10, timestamp: 50}},
+			nextStarts: map[string]seriesStart{"jvm.gc.duration{jvm.gc.cause=Allocation Failure}": {start: 60, timestamp: 70}},
+			want:       false,
+		},
+		{
+			name:       "first start timestamp of another metric",
+			starts:     map[string]seriesStart{"jvm.cpu.time{}": {start: 10, timestamp: 50}},
+			nextStarts: map[string]seriesStart{"jvm.gc.duration{}": {start: 60, timestamp: 70}},
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastSeen := time.Unix(1700000000, 0)
+			state := &jvmState{lastSeen: lastSeen}
+			state.checkRestart(tt.pid, tt.starts)
+			state.threads = threadState{peak: 42, totalStarted: 100}
+
+			assert.Equal(t, tt.want, state.checkRestart(tt.next, tt.nextStarts))
+			if tt.want {
+				assert.Equal(t, threadState{}, state.threads)
+			} else {
+				assert.Equal(t, threadState{peak: 42, totalStarted: 100}, state.threads)
+			}
+			assert.Equal(t, lastSeen, state.lastSeen)
+		})
+	}
+}
+
+func TestAddSeriesStarts(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("jvm.gc.duration")
+	histogram := metric.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for _, dp := range []struct {
+		gc               string
+		start, timestamp pcommon.Timestamp
+	}{
+		{gc: "G1 Young Generation", start: 10, timestamp: 50},
+		{gc: "G1 Old Generation", start: 20, timestamp: 50},
+		{gc: "G1 Young Generation", start: 10, timestamp: 40},
+	} {
+		point := histogram.DataPoints().AppendEmpty()
+		point.Attributes().PutStr("jvm.gc.name", dp.gc)
+		point.SetStartTimestamp(dp.start)
+		point.SetTimestamp(dp.timestamp)
+	}
+	// 增量指标没有起始时间
+	delta := pmetric.NewMetric()
+	delta.SetName("jvm.cpu.time")
+	delta.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	delta.Sum().DataPoints().AppendEmpty().SetStartTimestamp(30)
+
+	starts := make(map[string]seriesStart)
+	addSeriesStarts(starts, metric)
+	addSeriesStarts(starts, delta)
+	assert.Equal(t, map[string]seriesStart{
+		"jvm.gc.duration{jvm.gc.name=G1 Young Generation}": {start: 10, timestamp: 50},
+		"jvm.gc.duration{jvm.gc.name=G1 Old Generation}":   {start: 20, timestamp: 50},
+	}, starts)
+}
+
+func TestThreadStateUpdate(t *testing.T) {
+	tests := []struct {
+		name             string
+		observations     []threadObservation
+		wantPeak         int64
+		wantTotalStarted int64
+	}{
+		{
+			name: "peak follows the live thread count",
+			observations: []threadObservation{
+				{count: 20, hasCount: true},
+				{count: 35, hasCount: true},
+				{count: 25, hasCount: true},
+			},
+			wantPeak: 35,
+		},
+		{
+			name: "reported peak above the live thread count",
+			observations: []threadObservation{
+				{count: 20, hasCount: true, peak: 50, hasPeak: true},
+				{count: 30, hasCount: true},
+			},
+			wantPeak: 50,
+		},
+		{
+			name: "cumulative started count replaces the total",
+			observations: []threadObservation{
+				{started: 100, hasStarted: true},
+				{started: 140, hasStarted: true},
+			},
+			wantTotalStarted: 140,
+		},
+		{
+			name: "delta started counts are added up",
+			observations: []threadObservation{
+				{started: 100, startedIsDelta: true, hasStarted: true},
+				{started: 40, startedIsDelta: true, hasStarted: true},
+				{count: 10, hasCount: true},
+			},
+			wantPeak:         10,
+			wantTotalStarted: 140,
+		},
+		{
+			name: "started count above int32",
+			observations: []threadObservation{
+				{started: 3000000000, hasStarted: true},
+			},
+			wantTotalStarted: 3000000000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state threadState
+			var peak, totalStarted int64
+			for _, obs := range tt.observations {
+				peak, totalStarted = state.update(obs)
+			}
+			assert.Equal(t, tt.wantPeak, peak)
+			assert.Equal(t, tt.wantTotalStarted, totalStarted)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package jvm
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// scopeName is the instrumentation scope of the instruments of the JVM exporters.
+const scopeName = "github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+
+// exporterTelemetry holds the instruments the exporter uses to report on its own conversion work.
+type exporterTelemetry struct {
+	unmappedNames  metric.Int64Counter
+	typeMismatches metric.Int64Counter
+	spillSize      metric.Int64UpDownCounter
+	spillBytes     metric.Int64UpDownCounter
+	spillDropped   metric.Int64Counter
+}
+
+func newExporterTelemetry(set component.TelemetrySettings) (*exporterTelemetry, error) {
+	meterProvider := set.MeterProvider
+	if meterProvider == nil {
+		meterProvider = noop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(scopeName)
+
+	unmappedNames, err := meter.Int64Counter(
+		"exporter_jvm_unmapped_names",
+		metric.WithDescription("Number of JVM memory pool or garbage collector names without a configured mapping."),
+		metric.WithUnit("{names}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	typeMismatches, err := meter.Int64Counter(
+		"exporter_jvm_type_mismatches",
+		metric.WithDescription("Number of JVM metrics skipped because their type cannot be converted to the expected one."),
+		metric.WithUnit("{metrics}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	spillSize, err := meter.Int64UpDownCounter(
+		"exporter_jvm_spill_queue_size",
+		metric.WithDescription("Number of payloads stored on disk waiting to be replayed."),
+		metric.WithUnit("{payloads}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	spillBytes, err := meter.Int64UpDownCounter(
+		"exporter_jvm_spill_queue_bytes",
+		metric.WithDescription("Total size of the payloads stored on disk waiting to be replayed."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	spillDropped, err := meter.Int64Counter(
+		"exporter_jvm_spill_dropped",
+		metric.WithDescription("Number of stored payloads dropped before they could be replayed."),
+		metric.WithUnit("{payloads}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &exporterTelemetry{
+		unmappedNames:  unmappedNames,
+		typeMismatches: typeMismatches,
+		spillSize:      spillSize,
+		spillBytes:     spillBytes,
+		spillDropped:   spillDropped,
+	}, nil
+}
+
+// 名称本身只记录在日志中，避免属性基数随上报的数据增长
+func (t *exporterTelemetry) recordUnmappedName(kind string) {
+	t.unmappedNames.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("kind", kind),
+	))
+}
+
+func (t *exporterTelemetry) recordTypeMismatch(metricType string) {
+	t.typeMismatches.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("type", metricType),
+	))
+}
+
+func (t *exporterTelemetry) recordSpillQueue(signal string, payloads int64, bytes int64) {
+	attributes := metric.WithAttributes(attribute.String("signal", signal))
+	t.spillSize.Add(context.Background(), payloads, attributes)
+	t.spillBytes.Add(context.Background(), bytes, attributes)
+}
+
+func (t *exporterTelemetry) recordSpillDrop(signal string, reason string) {
+	t.spillDropped.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("signal", signal),
+		attribute.String("reason", reason),
+	))
+}
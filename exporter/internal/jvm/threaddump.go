@@ -0,0 +1,266 @@
+package jvm
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	// JVM_THREAD_DUMP_EVENT 是携带线程详情的日志事件名，每条日志记录描述一个线程，
+	// 日志内容为线程栈，可以是多行字符串或字符串数组
+	JVM_THREAD_DUMP_EVENT = "jvm.thread.dump"
+	EVENT_NAME            = "event.name"
+
+	THREAD_ID                  = "thread.id"
+	THREAD_NAME                = "thread.name"
+	JVM_THREAD_STATE           = "jvm.thread.state"
+	JVM_THREAD_LOCK_NAME       = "jvm.thread.lock.name"
+	JVM_THREAD_LOCK_OWNER_ID   = "jvm.thread.lock.owner.id"
+	JVM_THREAD_LOCK_OWNER_NAME = "jvm.thread.lock.owner.name"
+	JVM_THREAD_DEADLOCKED      = "jvm.thread.deadlocked"
+
+	// threadDumpExpiry 超过该时间仍未被指标取走的线程详情会被丢弃
+	threadDumpExpiry = 5 * time.Minute
+	// maxThreadDetails 每个 JVM 最多保留的线程详情数
+	maxThreadDetails = 200
+)
+
+// threadStateName 将 jvm.thread.state 的取值统一为 java.lang.Thread.State 的写法，
+// 如 timed_waiting、timed-waiting 都转换为 TIMED_WAITING
+func threadStateName(state string) string {
+	return strings.ToUpper(strings.ReplaceAll(state, "-", "_"))
+}
+
+// ThreadDetail 是单个线程的详情
+type ThreadDetail struct {
+	id            int64
+	name          string
+	state         string
+	lockName      string
+	lockOwnerID   int64
+	lockOwnerName string
+	deadlocked    bool
+	stackTrace    []string
+}
+
+// lockNames 返回线程详情中出现的锁名，按名称排序
+func lockNames(threads []ThreadDetail) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, thread := range threads {
+		if thread.lockName != "" && !seen[thread.lockName] {
+			seen[thread.lockName] = true
+			names = append(names, thread.lockName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// markDeadlocks 沿锁持有者查找等待环，环上的线程标记为死锁
+func markDeadlocks(threads []ThreadDetail) {
+	byID := make(map[int64]int, len(threads))
+	for i, thread := range threads {
+		byID[thread.id] = i
+	}
+	for i := range threads {
+		current, visited := i, make(map[int]bool)
+		for !visited[current] {
+			visited[current] = true
+			next, ok := byID[threads[current].lockOwnerID]
+			if threads[current].lockOwnerID == 0 || !ok {
+				break
+			}
+			if next == i {
+				threads[i].deadlocked = true
+				break
+			}
+			current = next
+		}
+	}
+}
+
+type threadDump struct {
+	received time.Time
+	threads  []ThreadDetail
+}
+
+// ThreadDumpStore 按 JVM 标识暂存日志中上报的线程详情，在该 JVM 下一次指标转换时取出
+type ThreadDumpStore struct {
+	mu    sync.Mutex
+	dumps map[string]*threadDump
+	// shared 不为空时存储由 AcquireThreadDumpStore 按组件 ID 共享
+	shared *component.ID
+}
+
+func NewThreadDumpStore() *ThreadDumpStore {
+	return &ThreadDumpStore{dumps: make(map[string]*threadDump)}
+}
+
+type sharedThreadDumpStore struct {
+	store *ThreadDumpStore
+	refs  int
+}
+
+var (
+	threadDumpStoresMu sync.Mutex
+	threadDumpStores   = make(map[component.ID]*sharedThreadDumpStore)
+)
+
+// AcquireThreadDumpStore 返回导出器共享的线程详情存储。同一个导出器的日志和指标由不同的实例处理，
+// 因此按组件 ID 共享；每个实例关闭时需要调用 Release
+func AcquireThreadDumpStore(id component.ID) *ThreadDumpStore {
+	threadDumpStoresMu.Lock()
+	defer threadDumpStoresMu.Unlock()
+	shared, ok := threadDumpStores[id]
+	if !ok {
+		store := NewThreadDumpStore()
+		store.shared = &id
+		shared = &sharedThreadDumpStore{store: store}
+		threadDumpStores[id] = shared
+	}
+	shared.refs++
+	return shared.store
+}
+
+// Release 释放 AcquireThreadDumpStore 获取的引用，最后一个实例释放后删除共享的存储，
+// 重新加载配置后的导出器不会沿用旧的存储
+func (s *ThreadDumpStore) Release() {
+	if s.shared == nil {
+		return
+	}
+	threadDumpStoresMu.Lock()
+	defer threadDumpStoresMu.Unlock()
+	shared, ok := threadDumpStores[*s.shared]
+	if !ok || shared.store != s {
+		return
+	}
+	shared.refs--
+	if shared.refs <= 0 {
+		delete(threadDumpStores, *s.shared)
+	}
+}
+
+// Consume 收集日志中的线程详情事件，返回收集到的线程数
+func (s *ThreadDumpStore) Consume(ld plog.Logs, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+
+	collected := 0
+	resourceLogs := ld.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		resourceLog := resourceLogs.At(i)
+		key := ResourceKey(resourceLog.Resource().Attributes())
+		scopeLogs := resourceLog.ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			logRecords := scopeLogs.At(j).LogRecords()
+			for k := 0; k < logRecords.Len(); k++ {
+				logRecord := logRecords.At(k)
+				if !IsThreadDumpEvent(logRecord) {
+					continue
+				}
+				dump, ok := s.dumps[key]
+				if !ok {
+					dump = &threadDump{}
+					s.dumps[key] = dump
+				}
+				dump.received = now
+				if len(dump.threads) < maxThreadDetails {
+					dump.threads = append(dump.threads, newThreadDetail(logRecord))
+					collected++
+				}
+			}
+		}
+	}
+	return collected
+}
+
+// take 取出 JVM 暂存的线程详情，取出后即从存储中删除
+func (s *ThreadDumpStore) take(key string, now time.Time) []ThreadDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	dump, ok := s.dumps[key]
+	if !ok {
+		return nil
+	}
+	delete(s.dumps, key)
+	markDeadlocks(dump.threads)
+	return dump.threads
+}
+
+func (s *ThreadDumpStore) expire(now time.Time) {
+	for key, dump := range s.dumps {
+		if now.Sub(dump.received) > threadDumpExpiry {
+			delete(s.dumps, key)
+		}
+	}
+}
+
+func IsThreadDumpEvent(logRecord plog.LogRecord) bool {
+	if logRecord.EventName() == JVM_THREAD_DUMP_EVENT {
+		return true
+	}
+	eventName, ok := logRecord.Attributes().Get(EVENT_NAME)
+	return ok && eventName.AsString() == JVM_THREAD_DUMP_EVENT
+}
+
+func newThreadDetail(logRecord plog.LogRecord) ThreadDetail {
+	attributes := logRecord.Attributes()
+	var thread ThreadDetail
+	if v, ok := attributes.Get(THREAD_ID); ok {
+		thread.id = IntAttribute(v)
+	}
+	if v, ok := attributes.Get(THREAD_NAME); ok {
+		thread.name = v.AsString()
+	}
+	if v, ok := attributes.Get(JVM_THREAD_STATE); ok {
+		thread.state = threadStateName(v.AsString())
+	}
+	if v, ok := attributes.Get(JVM_THREAD_LOCK_NAME); ok {
+		thread.lockName = v.AsString()
+	}
+	if v, ok := attributes.Get(JVM_THREAD_LOCK_OWNER_ID); ok {
+		thread.lockOwnerID = IntAttribute(v)
+	}
+	if v, ok := attributes.Get(JVM_THREAD_LOCK_OWNER_NAME); ok {
+		thread.lockOwnerName = v.AsString()
+	}
+	if v, ok := attributes.Get(JVM_THREAD_DEADLOCKED); ok {
+		thread.deadlocked = v.Bool()
+	}
+	body := logRecord.Body()
+	switch body.Type() {
+	case pcommon.ValueTypeSlice:
+		for i := 0; i < body.Slice().Len(); i++ {
+			thread.stackTrace = append(thread.stackTrace, body.Slice().At(i).AsString())
+		}
+	case pcommon.ValueTypeStr:
+		for _, line := range strings.Split(body.Str(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				thread.stackTrace = append(thread.stackTrace, line)
+			}
+		}
+	}
+	return thread
+}
+
+// IntAttribute 读取整数属性，兼容以字符串上报的线程 ID
+func IntAttribute(v pcommon.Value) int64 {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return v.Int()
+	case pcommon.ValueTypeDouble:
+		return int64(v.Double())
+	}
+	n, _ := strconv.ParseInt(v.AsString(), 10, 64)
+	return n
+}
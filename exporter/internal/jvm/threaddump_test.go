@@ -0,0 +1,182 @@
+package jvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// appendThreadDumpEvent 在 logs 中为 service.instance.id 为 instance 的 JVM 添加一条线程详情事件
+func appendThreadDumpEvent(logs plog.Logs, instance string, attributes map[string]any, stackTrace string) {
+	resourceLog := logs.ResourceLogs().AppendEmpty()
+	resourceLog.Resource().Attributes().PutStr(SERVICE_INSTANCE_ID, instance)
+	logRecord := resourceLog.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	logRecord.SetEventName(JVM_THREAD_DUMP_EVENT)
+	_ = logRecord.Attributes().FromRaw(attributes)
+	logRecord.Body().SetStr(stackTrace)
+}
+
+func TestMarkDeadlocks(t *testing.T) {
+	// thread 返回等待 owner 持有的锁的线程，owner 为 0 表示不等待锁
+	thread := func(id, owner int64) ThreadDetail {
+		return ThreadDetail{id: id, lockOwnerID: owner}
+	}
+	tests := []struct {
+		name    string
+		threads []ThreadDetail
+		want    []bool
+	}{
+		{
+			name:    "no locks",
+			threads: []ThreadDetail{thread(1, 0), thread(2, 0)},
+			want:    []bool{false, false},
+		},
+		{
+			name:    "waiting on a running thread",
+			threads: []ThreadDetail{thread(1, 2), thread(2, 0)},
+			want:    []bool{false, false},
+		},
+		{
+			name:    "two thread cycle",
+			threads: []ThreadDetail{thread(1, 2), thread(2, 1), thread(3, 0)},
+			want:    []bool{true, true, false},
+		},
+		{
+			name:    "three thread cycle with a waiter",
+			threads: []ThreadDetail{thread(1, 2), thread(2, 3), thread(3, 1), thread(4, 1)},
+			want:    []bool{true, true, true, false},
+		},
+		{
+			name:    "chain into a cycle",
+			threads: []ThreadDetail{thread(1, 2), thread(2, 3), thread(3, 2)},
+			want:    []bool{false, true, true},
+		},
+		{
+			name:    "owner not in the dump",
+			threads: []ThreadDetail{thread(1, 2), thread(3, 1)},
+			want:    []bool{false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markDeadlocks(tt.threads)
+			got := make([]bool, 0, len(tt.threads))
+			for _, thread := range tt.threads {
+				got = append(got, thread.deadlocked)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestThreadDumpStoreConsume(t *testing.T) {
+	now := time.Now()
+	logs := plog.NewLogs()
+	appendThreadDumpEvent(logs, "a", map[string]any{
+		THREAD_ID:                  "12",
+		THREAD_NAME:                "worker-1",
+		JVM_THREAD_STATE:           "timed-waiting",
+		JVM_THREAD_LOCK_NAME:       "java.lang.Object@1b6d3586",
+		JVM_THREAD_LOCK_OWNER_ID:   int64(13),
+		JVM_THREAD_LOCK_OWNER_NAME: "worker-2",
+	}, "at java.lang.Object.wait(Native Method)\n  at com.example.Worker.run(Worker.java:42)\n")
+	// 不是线程详情事件的日志被忽略
+	other := logs.ResourceLogs().AppendEmpty()
+	other.Resource().Attributes().PutStr(SERVICE_INSTANCE_ID, "a")
+	other.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("started")
+
+	store := NewThreadDumpStore()
+	assert.Equal(t, 1, store.Consume(logs, now))
+	assert.Equal(t, []ThreadDetail{{
+		id:            12,
+		name:          "worker-1",
+		state:         "TIMED_WAITING",
+		lockName:      "java.lang.Object@1b6d3586",
+		lockOwnerID:   13,
+		lockOwnerName: "worker-2",
+		stackTrace:    []string{"at java.lang.Object.wait(Native Method)", "at com.example.Worker.run(Worker.java:42)"},
+	}}, store.take("a", now))
+	// 取出后即删除
+	assert.Nil(t, store.take("a", now))
+}
+
+func TestThreadDumpStoreLimit(t *testing.T) {
+	now := time.Now()
+	logs := plog.NewLogs()
+	for i := 0; i < maxThreadDetails+50; i++ {
+		appendThreadDumpEvent(logs, "a", map[string]any{THREAD_ID: int64(i + 1)}, "")
+	}
+	appendThreadDumpEvent(logs, "b", map[string]any{THREAD_ID: int64(1)}, "")
+
+	store := NewThreadDumpStore()
+	assert.Equal(t, maxThreadDetails+1, store.Consume(logs, now))
+	assert.Len(t, store.take("a", now), maxThreadDetails)
+	assert.Len(t, store.take("b", now), 1)
+}
+
+func TestThreadDumpStoreExpiry(t *testing.T) {
+	now := time.Now()
+	logs := plog.NewLogs()
+	appendThreadDumpEvent(logs, "a", map[string]any{THREAD_ID: int64(1)}, "")
+	appendThreadDumpEvent(logs, "b", map[string]any{THREAD_ID: int64(1)}, "")
+
+	store := NewThreadDumpStore()
+	store.Consume(logs, now)
+	assert.Len(t, store.take("a", now.Add(threadDumpExpiry)), 1)
+	assert.Nil(t, store.take("b", now.Add(threadDumpExpiry+time.Second)))
+}
+
+func TestAcquireThreadDumpStore(t *testing.T) {
+	id := component.MustNewIDWithName("jvmhttp", "threaddump")
+	first := AcquireThreadDumpStore(id)
+	second := AcquireThreadDumpStore(id)
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, AcquireThreadDumpStore(component.MustNewIDWithName("jvmhttp", "other")))
+
+	// 仍有实例引用时共享的存储保留
+	first.Release()
+	assert.Same(t, first, AcquireThreadDumpStore(id))
+	second.Release()
+	first.Release()
+	// 所有引用释放后重新创建
+	third := AcquireThreadDumpStore(id)
+	assert.NotSame(t, first, third)
+	third.Release()
+	// 已删除的存储重复释放不影响新的存储
+	first.Release()
+	fourth := AcquireThreadDumpStore(id)
+	assert.NotSame(t, third, fourth)
+	fourth.Release()
+
+	// 未共享的存储释放时什么也不做
+	NewThreadDumpStore().Release()
+}
+
+func TestConverterTransformThreadDetails(t *testing.T) {
+	now := time.Now()
+	logs := plog.NewLogs()
+	appendThreadDumpEvent(logs, "a", map[string]any{THREAD_ID: int64(1), THREAD_NAME: "t1", JVM_THREAD_STATE: "blocked", JVM_THREAD_LOCK_NAME: "lock-b", JVM_THREAD_LOCK_OWNER_ID: int64(2)}, "at A.run")
+	appendThreadDumpEvent(logs, "a", map[string]any{THREAD_ID: int64(2), THREAD_NAME: "t2", JVM_THREAD_STATE: "blocked", JVM_THREAD_LOCK_NAME: "lock-a", JVM_THREAD_LOCK_OWNER_ID: int64(1)}, "at B.run")
+	store := NewThreadDumpStore()
+	store.Consume(logs, now)
+	converter, err := NewConverter(newTestConverterConfig(), store, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	newSumMetric(JVM_THREAD_COUNT, "{thread}", 2, map[string]any{JVM_THREAD_STATE: "blocked"}).CopyTo(appendJVMMetrics(md, map[string]any{SERVICE_INSTANCE_ID: "a"}).AppendEmpty())
+	snapshots := converter.Transform(md)
+	require.Len(t, snapshots, 1)
+	thread := snapshots[0].Message.Thread
+	assert.Equal(t, map[string]int64{"BLOCKED": 2}, thread.StateCounts)
+	assert.Equal(t, []string{"lock-a", "lock-b"}, thread.ThreadInfos.LockNames)
+	assert.Equal(t, [][]interface{}{
+		{int64(1), "t1", "BLOCKED", "lock-b", int64(2), "", true, []string{"at A.run"}},
+		{int64(2), "t2", "BLOCKED", "lock-a", int64(1), "", true, []string{"at B.run"}},
+	}, thread.ThreadInfos.ThreadInfo)
+}
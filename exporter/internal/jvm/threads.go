@@ -0,0 +1,65 @@
+package jvm
+
+import (
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// Micrometer 等来源会上报累计启动线程数和峰值线程数
+	JVM_THREADS_STARTED = "jvm.threads.started"
+	JVM_THREADS_PEAK    = "jvm.threads.peak"
+)
+
+// threadObservation 是一个批次中观测到的线程指标
+type threadObservation struct {
+	count       int64
+	daemonCount int64
+	hasCount    bool
+	// 按 jvm.thread.state 统计的线程数，没有该属性时为空
+	states map[string]int64
+
+	peak    int64
+	hasPeak bool
+
+	started        int64
+	startedIsDelta bool
+	hasStarted     bool
+}
+
+func (o *threadObservation) empty() bool {
+	return !o.hasCount && !o.hasPeak && !o.hasStarted
+}
+
+func (o *threadObservation) setStarted(isDelta bool, dataPoint pmetric.NumberDataPoint) {
+	o.startedIsDelta = isDelta
+	if o.startedIsDelta {
+		o.started += numberDataPointInt(dataPoint)
+	} else {
+		o.started = numberDataPointInt(dataPoint)
+	}
+	o.hasStarted = true
+}
+
+// threadState 保存单个 JVM 自进程启动以来的线程统计，进程重启时随 jvmState 一起重置
+type threadState struct {
+	peak         int64
+	totalStarted int64
+}
+
+// update 合并本批次的观测值，返回峰值线程数和累计启动线程数
+func (s *threadState) update(obs threadObservation) (int64, int64) {
+	if obs.hasCount && obs.count > s.peak {
+		s.peak = obs.count
+	}
+	if obs.hasPeak && obs.peak > s.peak {
+		s.peak = obs.peak
+	}
+	if obs.hasStarted {
+		if obs.startedIsDelta {
+			s.totalStarted += obs.started
+		} else {
+			s.totalStarted = obs.started
+		}
+	}
+	return s.peak, s.totalStarted
+}
@@ -0,0 +1,96 @@
+package jvmhttpexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+const (
+	// AckFormatOTLP reads the partial success of an OTLP ExportResponse.
+	AckFormatOTLP = "otlp"
+	// AckFormatJVM reads the acknowledgement returned by the JVM backend.
+	AckFormatJVM = "jvm"
+)
+
+// AckConfig defines how successful HTTP responses to converted JVM payloads are interpreted.
+type AckConfig struct {
+	// Format is "otlp" (default) or "jvm". With "jvm" the response body is read as
+	// {"code": 0, "message": "", "accepted": 1, "rejectedAgentIds": []}.
+	Format string `mapstructure:"format"`
+
+	// SuccessCodes are the ack codes of accepted requests. Rejected agentIds are logged and not
+	// retried. An ack without a code is accepted.
+	SuccessCodes []int `mapstructure:"success_codes"`
+
+	// RetryableCodes are the ack codes after which the request is retried. Any other code drops
+	// the request.
+	RetryableCodes []int `mapstructure:"retryable_codes"`
+}
+
+func newDefaultAckConfig() AckConfig {
+	return AckConfig{
+		Format:       AckFormatOTLP,
+		SuccessCodes: []int{0},
+	}
+}
+
+// Validate checks the ack configuration.
+func (cfg *AckConfig) Validate() error {
+	switch cfg.Format {
+	case AckFormatOTLP:
+		return nil
+	case AckFormatJVM:
+	default:
+		return fmt.Errorf("invalid format %q", cfg.Format)
+	}
+	if len(cfg.SuccessCodes) == 0 {
+		return errors.New("success_codes must not be empty")
+	}
+	for _, code := range cfg.RetryableCodes {
+		if slices.Contains(cfg.SuccessCodes, code) {
+			return fmt.Errorf("code %d is both a success and a retryable code", code)
+		}
+	}
+	return nil
+}
+
+// jvmAck 是 JVM 后端的确认响应
+type jvmAck struct {
+	Code             *int     `json:"code"`
+	Message          string   `json:"message"`
+	Accepted         int64    `json:"accepted"`
+	RejectedAgentIds []string `json:"rejectedAgentIds"`
+}
+
+// newAckHandler 返回解析 JVM 后端确认响应的 partialSuccessHandler
+func newAckHandler(cfg AckConfig, logger *zap.Logger) partialSuccessHandler {
+	return func(body []byte, contentType string) error {
+		if len(body) == 0 {
+			return nil
+		}
+		var ack jvmAck
+		if err := json.Unmarshal(body, &ack); err != nil {
+			return fmt.Errorf("error parsing ack response: %w", err)
+		}
+		switch {
+		case ack.Code == nil || slices.Contains(cfg.SuccessCodes, *ack.Code):
+			if len(ack.RejectedAgentIds) > 0 {
+				logger.Warn("Partial success response",
+					zap.String("message", ack.Message),
+					zap.Int64("accepted", ack.Accepted),
+					zap.Strings("rejected_agent_ids", ack.RejectedAgentIds),
+				)
+			}
+			return nil
+		case slices.Contains(cfg.RetryableCodes, *ack.Code):
+			return fmt.Errorf("%w: backend responded with retryable ack code %d: %s", errEndpointBusy, *ack.Code, ack.Message)
+		default:
+			return consumererror.NewPermanent(fmt.Errorf("backend responded with ack code %d: %s", *ack.Code, ack.Message))
+		}
+	}
+}
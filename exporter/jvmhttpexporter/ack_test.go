@@ -0,0 +1,104 @@
+package jvmhttpexporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+func TestAckConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AckConfig
+		wantErr string
+	}{
+		{name: "default", cfg: newDefaultAckConfig()},
+		{name: "jvm", cfg: AckConfig{Format: AckFormatJVM, SuccessCodes: []int{0}, RetryableCodes: []int{503}}},
+		{name: "unknown format", cfg: AckConfig{Format: "xml"}, wantErr: `invalid format "xml"`},
+		{name: "no success codes", cfg: AckConfig{Format: AckFormatJVM}, wantErr: "success_codes must not be empty"},
+		{name: "overlapping codes", cfg: AckConfig{Format: AckFormatJVM, SuccessCodes: []int{0, 1}, RetryableCodes: []int{1}}, wantErr: "code 1 is both a success and a retryable code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAckHandler(t *testing.T) {
+	cfg := AckConfig{Format: AckFormatJVM, SuccessCodes: []int{0, 200}, RetryableCodes: []int{503}}
+	tests := []struct {
+		name          string
+		body          string
+		wantErr       bool
+		wantPermanent bool
+		wantBusy      bool
+	}{
+		{name: "empty body", body: ""},
+		{name: "success", body: `{"code":0,"message":"ok","accepted":2}`},
+		{name: "other success code", body: `{"code":200}`},
+		{name: "no code", body: `{"message":"ok"}`},
+		{name: "partial success", body: `{"code":0,"accepted":1,"rejectedAgentIds":["agent-1"]}`},
+		{name: "retryable code", body: `{"code":503,"message":"busy"}`, wantErr: true, wantBusy: true},
+		{name: "rejected", body: `{"code":400,"message":"bad agentId"}`, wantErr: true, wantPermanent: true},
+		{name: "invalid json", body: `not json`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAckHandler(cfg, zap.NewNop())([]byte(tt.body), jsonContentType)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantPermanent, consumererror.IsPermanent(err))
+			assert.Equal(t, tt.wantBusy, errors.Is(err, errEndpointBusy))
+		})
+	}
+}
+
+func TestPayloadHandlerUsesAckForJSONResponses(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		contentType   string
+		body          string
+		wantPermanent bool
+	}{
+		{name: "jvm ack", format: AckFormatJVM, contentType: jsonContentType, body: `{"code":400}`, wantPermanent: true},
+		{name: "jvm ack with charset", format: AckFormatJVM, contentType: jsonContentType + "; charset=utf-8", body: `{"code":400}`, wantPermanent: true},
+		{name: "otlp ack ignores the jvm format", format: AckFormatOTLP, contentType: jsonContentType, body: `{"code":400}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Ack = AckConfig{Format: tt.format, SuccessCodes: []int{0}}
+			e := newTestExporter(t, cfg)
+			e.metricsURL = server.URL
+
+			err := e.sendPayload(context.Background(), payloadKindMetrics, []byte(`{}`))
+			if !tt.wantPermanent {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, consumererror.IsPermanent(err))
+		})
+	}
+}
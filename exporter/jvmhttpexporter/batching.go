@@ -0,0 +1,121 @@
+package jvmhttpexporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const (
+	// BatchFormatNone sends every envelope in its own request.
+	BatchFormatNone = "none"
+	// BatchFormatJSONArray sends a JSON array of envelopes.
+	BatchFormatJSONArray = "json_array"
+	// BatchFormatNDJSON sends one envelope per line.
+	BatchFormatNDJSON = "ndjson"
+
+	defaultBatchMaxItems = 100
+	defaultBatchMaxBytes = 4 << 20
+
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// errPayloadTooLarge 表示后端以 413 拒绝了请求体
+var errPayloadTooLarge = errors.New("payload too large")
+
+// BatchingConfig defines how the envelopes of one export are packed into HTTP requests.
+type BatchingConfig struct {
+	// Format is "none" (default), "json_array" or "ndjson". Batches that are spilled to disk are
+	// replayed one envelope per request in the same format.
+	Format string `mapstructure:"format"`
+
+	// MaxItems is the maximum number of envelopes in one request.
+	MaxItems int `mapstructure:"max_items"`
+
+	// MaxBytes is the maximum size of one request body before compression. An envelope larger
+	// than MaxBytes is sent alone.
+	MaxBytes int `mapstructure:"max_bytes"`
+}
+
+func newDefaultBatchingConfig() BatchingConfig {
+	return BatchingConfig{
+		Format:   BatchFormatNone,
+		MaxItems: defaultBatchMaxItems,
+		MaxBytes: defaultBatchMaxBytes,
+	}
+}
+
+// Validate checks the batching configuration.
+func (cfg *BatchingConfig) Validate() error {
+	switch cfg.Format {
+	case BatchFormatNone:
+		return nil
+	case BatchFormatJSONArray, BatchFormatNDJSON:
+	default:
+		return fmt.Errorf("invalid format %q", cfg.Format)
+	}
+	if cfg.MaxItems <= 0 {
+		return errors.New("max_items must be positive")
+	}
+	if cfg.MaxBytes <= 0 {
+		return errors.New("max_bytes must be positive")
+	}
+	return nil
+}
+
+func (cfg *BatchingConfig) enabled() bool {
+	return cfg.Format != BatchFormatNone
+}
+
+// contentType 返回请求体的 Content-Type
+func (cfg *BatchingConfig) contentType() string {
+	if cfg.Format == BatchFormatNDJSON {
+		return ndjsonContentType
+	}
+	return jsonContentType
+}
+
+// batches 按 MaxItems 和 MaxBytes 将请求体依次分组，保持原有顺序
+func (cfg *BatchingConfig) batches(items [][]byte) [][][]byte {
+	var batches [][][]byte
+	var batch [][]byte
+	size := 0
+	for _, item := range items {
+		// 分隔符和数组括号各占一个字节
+		itemSize := len(item) + 1
+		if len(batch) > 0 && (len(batch) >= cfg.MaxItems || size+itemSize > cfg.MaxBytes) {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+		if len(batch) == 0 {
+			size = 1
+		}
+		batch = append(batch, item)
+		size += itemSize
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// encode 将一组请求体编码为一个请求体
+func (cfg *BatchingConfig) encode(items [][]byte) []byte {
+	var buf bytes.Buffer
+	if cfg.Format == BatchFormatNDJSON {
+		for _, item := range items {
+			buf.Write(item)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes()
+	}
+	buf.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(item)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
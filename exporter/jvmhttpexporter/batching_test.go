@@ -0,0 +1,219 @@
+package jvmhttpexporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+func testEnvelopes(n int) [][]byte {
+	envelopes := make([][]byte, n)
+	for i := range envelopes {
+		envelopes[i] = []byte(`{"n":` + strconv.Itoa(i) + `}`)
+	}
+	return envelopes
+}
+
+func TestBatchingConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     BatchingConfig
+		wantErr string
+	}{
+		{name: "default", cfg: newDefaultBatchingConfig()},
+		{name: "none ignores limits", cfg: BatchingConfig{Format: BatchFormatNone}},
+		{name: "ndjson", cfg: BatchingConfig{Format: BatchFormatNDJSON, MaxItems: 10, MaxBytes: 1024}},
+		{name: "unknown format", cfg: BatchingConfig{Format: "xml"}, wantErr: `invalid format "xml"`},
+		{name: "no max items", cfg: BatchingConfig{Format: BatchFormatJSONArray, MaxBytes: 1024}, wantErr: "max_items must be positive"},
+		{name: "no max bytes", cfg: BatchingConfig{Format: BatchFormatJSONArray, MaxItems: 10}, wantErr: "max_bytes must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBatchingConfigBatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxItems int
+		maxBytes int
+		items    []string
+		want     [][]string
+	}{
+		{
+			name:     "max items",
+			maxItems: 2,
+			maxBytes: 1024,
+			items:    []string{"a", "b", "c", "d", "e"},
+			want:     [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			// 每个请求体加一个分隔符，再加一个括号
+			name:     "max bytes",
+			maxItems: 100,
+			maxBytes: 7,
+			items:    []string{"aa", "bb", "cc"},
+			want:     [][]string{{"aa", "bb"}, {"cc"}},
+		},
+		{
+			name:     "envelope larger than max bytes is sent alone",
+			maxItems: 100,
+			maxBytes: 4,
+			items:    []string{"a", "bbbbbbbb", "c"},
+			want:     [][]string{{"a"}, {"bbbbbbbb"}, {"c"}},
+		},
+		{
+			name:     "empty",
+			maxItems: 10,
+			maxBytes: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := BatchingConfig{Format: BatchFormatJSONArray, MaxItems: tt.maxItems, MaxBytes: tt.maxBytes}
+			items := make([][]byte, len(tt.items))
+			for i, item := range tt.items {
+				items[i] = []byte(item)
+			}
+			var got [][]string
+			for _, batch := range cfg.batches(items) {
+				var strs []string
+				for _, item := range batch {
+					strs = append(strs, string(item))
+				}
+				got = append(got, strs)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBatchingConfigEncode(t *testing.T) {
+	items := [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`)}
+	tests := []struct {
+		format          string
+		items           [][]byte
+		want            string
+		wantContentType string
+	}{
+		{format: BatchFormatJSONArray, items: items, want: `[{"a":1},{"b":2}]`, wantContentType: jsonContentType},
+		{format: BatchFormatJSONArray, items: items[:1], want: `[{"a":1}]`, wantContentType: jsonContentType},
+		{format: BatchFormatNDJSON, items: items, want: "{\"a\":1}\n{\"b\":2}\n", wantContentType: ndjsonContentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := BatchingConfig{Format: tt.format}
+			assert.Equal(t, tt.want, string(cfg.encode(tt.items)))
+			assert.Equal(t, tt.wantContentType, cfg.contentType())
+		})
+	}
+}
+
+func TestDeliverBatchSplitsTooLargeBatches(t *testing.T) {
+	tests := []struct {
+		name string
+		// maxAccepted 为后端接受的最大条数，超过时返回 413
+		maxAccepted int
+		envelopes   int
+		wantSizes   []int
+		wantErr     bool
+	}{
+		{name: "accepted", maxAccepted: 4, envelopes: 4, wantSizes: []int{4}},
+		{name: "split once", maxAccepted: 2, envelopes: 4, wantSizes: []int{4, 2, 2}},
+		{name: "split down to single envelopes", maxAccepted: 1, envelopes: 4, wantSizes: []int{4, 2, 1, 1, 2, 1, 1}},
+		{name: "uneven split", maxAccepted: 2, envelopes: 5, wantSizes: []int{5, 2, 3, 1, 2}},
+		{name: "single envelope too large", maxAccepted: 0, envelopes: 1, wantSizes: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var sizes []int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var batch []json.RawMessage
+				assert.NoError(t, json.Unmarshal(body, &batch))
+				mu.Lock()
+				sizes = append(sizes, len(batch))
+				mu.Unlock()
+				if len(batch) > tt.maxAccepted {
+					w.WriteHeader(http.StatusRequestEntityTooLarge)
+				}
+			}))
+			defer server.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.Batching = BatchingConfig{Format: BatchFormatJSONArray, MaxItems: 100, MaxBytes: 1 << 20}
+			e := newTestExporter(t, cfg)
+			e.metricsURL = server.URL
+
+			err := e.deliverAll(context.Background(), payloadKindMetrics, testEnvelopes(tt.envelopes))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errPayloadTooLarge)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantSizes, sizes)
+		})
+	}
+}
+
+func TestSpilledBatchesReplayPerEnvelope(t *testing.T) {
+	var available atomic.Bool
+	var mu sync.Mutex
+	var replayed []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var batch []json.RawMessage
+		assert.NoError(t, json.Unmarshal(body, &batch))
+		mu.Lock()
+		replayed = append(replayed, len(batch))
+		mu.Unlock()
+		if len(batch) > 1 {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Batching = BatchingConfig{Format: BatchFormatJSONArray, MaxItems: 100, MaxBytes: 1 << 20}
+	cfg.Spill = jvm.SpillConfig{Directory: t.TempDir(), MaxBytes: 1 << 20, MaxAge: time.Hour, ReplayInterval: 10 * time.Millisecond}
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL
+	var err error
+	e.spill, err = jvm.NewSpillQueue(cfg.Spill, component.MustNewID("jvmhttp"), "metrics", e.sendPayload, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	require.NoError(t, e.spill.Start())
+
+	require.NoError(t, e.deliverAll(context.Background(), payloadKindMetrics, testEnvelopes(3)))
+	assert.Equal(t, 3, e.spill.Pending())
+
+	available.Store(true)
+	assert.Eventually(t, func() bool { return e.spill.Pending() == 0 }, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{1, 1, 1}, replayed)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"net/url"
 
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configretry"
@@ -55,17 +57,174 @@ type Config struct {
 	// The URL to send logs to. If omitted the Endpoint + "/v1/logs" will be used.
 	LogsEndpoint string `mapstructure:"logs_endpoint"`
 
-	// The encoding to export telemetry (default: "proto")
+	// The URL to send offline events to. If omitted JVMs are only reported offline through
+	// their final metrics message. Requires offline.timeout. With failover.endpoints it may be
+	// a path such as "/v1/offline" that is appended to each failover endpoint.
+	OfflineEndpoint string `mapstructure:"offline_endpoint"`
+
+	// The encoding to export telemetry (default: "json"). With "proto" JVM metrics are sent as a
+	// metrics.ExportRequest, the message used by the jvmx exporter, and forwarded signals as OTLP
+	// protobuf. metrics.ExportRequest has no envelope, so the logType and masterIp of the JSON
+	// envelope are not sent with JVM metrics. Converted transactions and logs are always JSON.
 	Encoding EncodingType `mapstructure:"encoding"`
+
+	// Conversion selects the signals converted into JVM backend payloads. Other signals are
+	// forwarded as OTLP.
+	Conversion ConversionConfig `mapstructure:"conversion"`
+
+	// Identity configures how agentId, multiAgentId and masterIp of JVM payloads are built.
+	Identity IdentityConfig `mapstructure:"identity"`
+
+	// NameMapping configures how memory pool and garbage collector names are reported.
+	NameMapping jvm.NameMappingConfig `mapstructure:"name_mapping"`
+
+	// CPU configures how the CPU section of JVM payloads is computed.
+	CPU jvm.CPUConfig `mapstructure:"cpu"`
+
+	// Input configures which instrumentation scopes and metric naming dialects are converted.
+	Input jvm.InputConfig `mapstructure:"input"`
+
+	// LeakDetection configures when database connection pools are reported as leak suspects.
+	LeakDetection jvm.LeakDetectionConfig `mapstructure:"leak_detection"`
+
+	// TimestampFormat is the format of appStartTime and creationTime: "unix_milli" (default),
+	// "unix", "rfc3339" or a Go time layout such as "2006-01-02 15:04:05".
+	TimestampFormat string `mapstructure:"timestamp_format"`
+
+	// Health configures the rules that compute the status field of JVM payloads.
+	Health jvm.HealthConfig `mapstructure:"health"`
+
+	// Assembly configures how metrics of the same JVM from different batches are combined
+	// into one snapshot per interval.
+	Assembly jvm.AssemblyConfig `mapstructure:"assembly"`
+
+	// LogTypes configures the logType of the payload envelope per signal.
+	LogTypes LogTypesConfig `mapstructure:"log_types"`
+
+	// Offline configures how JVMs that stop reporting are reported offline.
+	Offline jvm.OfflineConfig `mapstructure:"offline"`
+
+	// Spill configures persisting converted payloads to disk while the backend is unavailable.
+	Spill jvm.SpillConfig `mapstructure:"spill"`
+
+	// Batching configures packing the envelopes of one export into fewer requests.
+	Batching BatchingConfig `mapstructure:"batching"`
+
+	// Ack configures how responses to converted JVM payloads are interpreted.
+	Ack AckConfig `mapstructure:"ack"`
+
+	// Failover configures a list of endpoints used instead of endpoint.
+	Failover FailoverConfig `mapstructure:"failover"`
+
+	// Traces overrides the transport settings of traces.
+	Traces SignalConfig `mapstructure:"traces"`
+
+	// Metrics overrides the transport settings of metrics.
+	Metrics SignalConfig `mapstructure:"metrics"`
+
+	// Logs overrides the transport settings of logs.
+	Logs SignalConfig `mapstructure:"logs"`
+
+	// Profiles overrides the transport settings of profiles.
+	Profiles SignalConfig `mapstructure:"profiles"`
 }
 
 var _ component.Config = (*Config)(nil)
 
+// ConversionConfig selects the signals that are converted into JVM backend payloads instead of
+// being forwarded as OTLP.
+type ConversionConfig struct {
+	// Traces converts server and consumer spans into transaction payloads. Spans that are not
+	// part of a transaction are dropped. Disabled by default: spans are forwarded as OTLP to
+	// the traces URL unless this is enabled.
+	Traces bool `mapstructure:"traces"`
+
+	// Logs converts log records into log payloads. Disabled by default: log records are
+	// forwarded as OTLP to the logs URL unless this is enabled. JVM thread dumps are collected
+	// from the logs either way.
+	Logs bool `mapstructure:"logs"`
+}
+
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	logger.Info("Validate config .......")
-	if cfg.Endpoint == "" && cfg.TracesEndpoint == "" && cfg.MetricsEndpoint == "" && cfg.LogsEndpoint == "" {
+	if cfg.Endpoint == "" && cfg.TracesEndpoint == "" && cfg.MetricsEndpoint == "" && cfg.LogsEndpoint == "" &&
+		cfg.Traces.Endpoint == "" && cfg.Metrics.Endpoint == "" && cfg.Logs.Endpoint == "" && cfg.Profiles.Endpoint == "" &&
+		len(cfg.Failover.Endpoints) == 0 {
 		return errors.New("at least one endpoint must be specified")
 	}
+	converterConfig := cfg.converterConfig()
+	if err := converterConfig.Validate(); err != nil {
+		return err
+	}
+	// 转换后的交易和日志固定为 JSON
+	if cfg.Conversion.Traces && cfg.Traces.encoding(cfg.Encoding) == EncodingProto {
+		return errors.New("conversion.traces requires the json encoding")
+	}
+	if cfg.Conversion.Logs && cfg.Logs.encoding(cfg.Encoding) == EncodingProto {
+		return errors.New("conversion.logs requires the json encoding")
+	}
+	if err := cfg.Assembly.Validate(); err != nil {
+		return fmt.Errorf("assembly: %w", err)
+	}
+	if err := cfg.LogTypes.Validate(); err != nil {
+		return fmt.Errorf("log_types: %w", err)
+	}
+	if err := cfg.Offline.Validate(); err != nil {
+		return fmt.Errorf("offline: %w", err)
+	}
+	if cfg.OfflineEndpoint != "" && cfg.Offline.Timeout == 0 {
+		return errors.New("offline_endpoint requires offline.timeout")
+	}
+	if err := cfg.Spill.Validate(); err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+	if err := cfg.Batching.Validate(); err != nil {
+		return fmt.Errorf("batching: %w", err)
+	}
+	if err := cfg.Ack.Validate(); err != nil {
+		return fmt.Errorf("ack: %w", err)
+	}
+	if err := cfg.Failover.Validate(); err != nil {
+		return fmt.Errorf("failover: %w", err)
+	}
+	if cfg.Endpoint != "" && len(cfg.Failover.Endpoints) > 0 {
+		return errors.New("endpoint and failover.endpoints must not both be specified")
+	}
+	if cfg.OfflineEndpoint != "" && len(cfg.Failover.Endpoints) == 0 {
+		if offlineURL, err := url.Parse(cfg.OfflineEndpoint); err != nil || !offlineURL.IsAbs() {
+			return errors.New("offline_endpoint must be an absolute URL unless failover.endpoints is specified")
+		}
+	}
+	for _, signal := range []struct {
+		name   string
+		cfg    *SignalConfig
+		legacy string
+	}{
+		{name: "traces", cfg: &cfg.Traces, legacy: cfg.TracesEndpoint},
+		{name: "metrics", cfg: &cfg.Metrics, legacy: cfg.MetricsEndpoint},
+		{name: "logs", cfg: &cfg.Logs, legacy: cfg.LogsEndpoint},
+		{name: "profiles", cfg: &cfg.Profiles},
+	} {
+		if err := signal.cfg.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", signal.name, err)
+		}
+		if signal.cfg.Endpoint != "" && signal.legacy != "" {
+			return fmt.Errorf("%s.endpoint and %s_endpoint must not both be specified", signal.name, signal.name)
+		}
+	}
 	return nil
 }
+
+// converterConfig 返回共享转换器使用的配置
+func (cfg *Config) converterConfig() jvm.ConverterConfig {
+	return jvm.ConverterConfig{
+		Identity:        cfg.Identity.IdentityConfig,
+		MasterIP:        cfg.Identity.MasterIP,
+		NameMapping:     cfg.NameMapping,
+		CPU:             cfg.CPU,
+		Input:           cfg.Input,
+		LeakDetection:   cfg.LeakDetection,
+		TimestampFormat: cfg.TimestampFormat,
+		Health:          cfg.Health,
+	}
+}
@@ -0,0 +1,117 @@
+package jvmhttpexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:   "default with endpoint",
+			modify: func(cfg *Config) { cfg.Endpoint = "http://localhost:4318" },
+		},
+		{
+			name: "custom identity templates",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Identity.AgentID = "${service.name}@${host.name|k8s.pod.name:-unknown}"
+				cfg.Identity.MultiAgentID = "${service.namespace:-default}/${service.name}"
+				cfg.Identity.MasterIP = "${k8s.node.ip}"
+			},
+		},
+		{
+			name: "unclosed agent_id placeholder",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Identity.AgentID = "${service.name"
+			},
+			wantErr: `identity: invalid agent_id template: unclosed placeholder in "${service.name"`,
+		},
+		{
+			name: "empty multi_agent_id attribute",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Identity.MultiAgentID = "${}"
+			},
+			wantErr: "identity: invalid multi_agent_id template: empty attribute name in placeholder",
+		},
+		{
+			name: "unclosed master_ip placeholder",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Identity.MasterIP = "${host.ip|k8s.pod.ip"
+			},
+			wantErr: `identity: invalid master_ip template: unclosed placeholder in "${host.ip|k8s.pod.ip"`,
+		},
+		{
+			name: "proto for converted traces",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Conversion.Traces = true
+				cfg.Encoding = EncodingProto
+			},
+			wantErr: "conversion.traces requires the json encoding",
+		},
+		{
+			name: "proto for converted logs",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Conversion.Logs = true
+				cfg.Encoding = EncodingProto
+			},
+			wantErr: "conversion.logs requires the json encoding",
+		},
+		{
+			name: "offline endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.OfflineEndpoint = "http://localhost:4318/v1/offline"
+				cfg.Offline.Timeout = time.Minute
+			},
+		},
+		{
+			name: "offline endpoint without timeout",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.OfflineEndpoint = "http://localhost:4318/v1/offline"
+			},
+			wantErr: "offline_endpoint requires offline.timeout",
+		},
+		{
+			name: "relative offline endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.OfflineEndpoint = "/v1/offline"
+				cfg.Offline.Timeout = time.Minute
+			},
+			wantErr: "offline_endpoint must be an absolute URL unless failover.endpoints is specified",
+		},
+		{
+			name: "relative offline endpoint with failover",
+			modify: func(cfg *Config) {
+				cfg.Failover.Endpoints = []string{"http://a:4318", "http://b:4318"}
+				cfg.OfflineEndpoint = "/v1/offline"
+				cfg.Offline.Timeout = time.Minute
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package jvmhttpexporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.uber.org/zap"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -54,14 +56,41 @@ func createMetrics(
 		return nil, err
 	}
 	oCfg := cfg.(*Config)
-	oce.metricsURL, err = composeSignalURL(oCfg, oCfg.MetricsEndpoint, "metrics", "v1")
+	oce.signal = oCfg.Metrics
+	oce.converter.encoding = oce.encoding()
+	oce.metricsURL, err = composeSignalURL(oCfg, oCfg.Metrics.endpoint(oCfg.MetricsEndpoint), "metrics", "v1")
 	set.Logger.Info("metrics URL：" + oce.metricsURL)
 	if err != nil {
 		return nil, err
 	}
+	if oce.endpoints, err = composeSignalEndpoints(oCfg, oCfg.Metrics.endpoint(oCfg.MetricsEndpoint), "metrics", "v1", set.TelemetrySettings); err != nil {
+		return nil, err
+	}
+	if oCfg.Assembly.Interval > 0 {
+		oce.assembler = jvm.NewAssembler(oCfg.Assembly, oce.converter, oce.flushMetrics, set.Logger)
+	}
+	if oCfg.Offline.Timeout > 0 {
+		oce.offline = jvm.NewOfflineTracker(oCfg.Offline, oce.sendOffline, set.Logger)
+		oce.converter.TrackOffline(oce.offline)
+		if oCfg.OfflineEndpoint != "" {
+			if _, err = url.Parse(oCfg.OfflineEndpoint); err != nil {
+				return nil, errors.New("offline_endpoint must be a valid URL")
+			}
+			oce.offlineURL = oCfg.OfflineEndpoint
+			if oce.offlineEndpoints, err = composeOfflineEndpoints(oCfg, set.TelemetrySettings); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if oCfg.Spill.Directory != "" {
+		if oce.spill, err = jvm.NewSpillQueue(oCfg.Spill, set.ID, "metrics", oce.sendPayload, set.TelemetrySettings); err != nil {
+			return nil, err
+		}
+	}
 	return exporterhelper.NewMetrics(ctx, set, cfg,
 		oce.pushMetrics,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
@@ -79,10 +108,24 @@ func createDefaultConfig() component.Config {
 	clientConfig.WriteBufferSize = 512 * 1024
 
 	return &Config{
-		RetryConfig:  configretry.NewDefaultBackOffConfig(),
-		QueueConfig:  exporterhelper.NewDefaultQueueConfig(),
-		Encoding:     EncodingJSON,
-		ClientConfig: clientConfig,
+		RetryConfig:     configretry.NewDefaultBackOffConfig(),
+		QueueConfig:     exporterhelper.NewDefaultQueueConfig(),
+		Encoding:        EncodingJSON,
+		ClientConfig:    clientConfig,
+		Identity:        newDefaultIdentityConfig(),
+		NameMapping:     jvm.NewDefaultNameMappingConfig(),
+		CPU:             jvm.NewDefaultCPUConfig(),
+		Input:           jvm.NewDefaultInputConfig(),
+		LeakDetection:   jvm.NewDefaultLeakDetectionConfig(),
+		TimestampFormat: jvm.DefaultTimestampFormat,
+		Health:          jvm.NewDefaultHealthConfig(),
+		Assembly:        jvm.NewDefaultAssemblyConfig(),
+		LogTypes:        newDefaultLogTypesConfig(),
+		Offline:         jvm.NewDefaultOfflineConfig(),
+		Spill:           jvm.NewDefaultSpillConfig(),
+		Batching:        newDefaultBatchingConfig(),
+		Ack:             newDefaultAckConfig(),
+		Failover:        newDefaultFailoverConfig(),
 	}
 }
 
@@ -99,14 +142,48 @@ func composeSignalURL(oCfg *Config, signalOverrideURL string, signalName string,
 			return "", fmt.Errorf("%s_endpoint must be a valid URL", signalName)
 		}
 		return signalOverrideURL, nil
-	case oCfg.Endpoint == "":
+	case oCfg.Endpoint == "" && len(oCfg.Failover.Endpoints) == 0:
 		return "", fmt.Errorf("either endpoint or %s_endpoint must be specified", signalName)
+	case oCfg.Endpoint == "":
+		// 实际发送地址由 failover 选择
+		return appendSignalPath(oCfg.Failover.Endpoints[0], signalName, signalVersion), nil
 	default:
-		if strings.HasSuffix(oCfg.Endpoint, "/") {
-			return oCfg.Endpoint + signalVersion + "/" + signalName, nil
-		}
-		return oCfg.Endpoint + "/" + signalVersion + "/" + signalName, nil
+		return appendSignalPath(oCfg.Endpoint, signalName, signalVersion), nil
+	}
+}
+
+func appendSignalPath(endpoint string, signalName string, signalVersion string) string {
+	if strings.HasSuffix(endpoint, "/") {
+		return endpoint + signalVersion + "/" + signalName
+	}
+	return endpoint + "/" + signalVersion + "/" + signalName
+}
+
+// composeSignalEndpoints creates the failover endpoints of the signal. It returns nil when the signal
+// has its own URL or no failover endpoints are configured.
+func composeSignalEndpoints(oCfg *Config, signalOverrideURL string, signalName string, signalVersion string, set component.TelemetrySettings) (*endpointPool, error) {
+	if signalOverrideURL != "" || len(oCfg.Failover.Endpoints) == 0 {
+		return nil, nil
 	}
+	urls := make([]string, 0, len(oCfg.Failover.Endpoints))
+	for _, endpoint := range oCfg.Failover.Endpoints {
+		urls = append(urls, appendSignalPath(endpoint, signalName, signalVersion))
+	}
+	return newEndpointPool(oCfg.Failover, urls, signalName, set)
+}
+
+// composeOfflineEndpoints creates the failover endpoints of offline events. It returns nil when
+// offline_endpoint is an absolute URL or no failover endpoints are configured.
+func composeOfflineEndpoints(oCfg *Config, set component.TelemetrySettings) (*endpointPool, error) {
+	offlineURL, err := url.Parse(oCfg.OfflineEndpoint)
+	if err != nil || offlineURL.IsAbs() || len(oCfg.Failover.Endpoints) == 0 {
+		return nil, err
+	}
+	urls := make([]string, 0, len(oCfg.Failover.Endpoints))
+	for _, endpoint := range oCfg.Failover.Endpoints {
+		urls = append(urls, strings.TrimSuffix(endpoint, "/")+"/"+strings.TrimPrefix(oCfg.OfflineEndpoint, "/"))
+	}
+	return newEndpointPool(oCfg.Failover, urls, payloadKindOffline, set)
 }
 
 func createTraces(
@@ -121,14 +198,25 @@ func createTraces(
 	}
 	oCfg := cfg.(*Config)
 
-	oce.tracesURL, err = composeSignalURL(oCfg, oCfg.TracesEndpoint, "traces", "v1")
+	oce.signal = oCfg.Traces
+	oce.tracesURL, err = composeSignalURL(oCfg, oCfg.Traces.endpoint(oCfg.TracesEndpoint), "traces", "v1")
 	if err != nil {
 		return nil, err
 	}
+	if oce.endpoints, err = composeSignalEndpoints(oCfg, oCfg.Traces.endpoint(oCfg.TracesEndpoint), "traces", "v1", set.TelemetrySettings); err != nil {
+		return nil, err
+	}
+	// 只有转换后的交易会落盘
+	if oCfg.Spill.Directory != "" && oCfg.Conversion.Traces {
+		if oce.spill, err = jvm.NewSpillQueue(oCfg.Spill, set.ID, "traces", oce.sendPayload, set.TelemetrySettings); err != nil {
+			return nil, err
+		}
+	}
 
 	return exporterhelper.NewTraces(ctx, set, cfg,
 		oce.pushTraces,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
@@ -146,14 +234,25 @@ func createLogs(
 		return nil, err
 	}
 	oCfg := cfg.(*Config)
-	oce.logsURL, err = composeSignalURL(oCfg, oCfg.LogsEndpoint, "logs", "v1")
+	oce.signal = oCfg.Logs
+	oce.logsURL, err = composeSignalURL(oCfg, oCfg.Logs.endpoint(oCfg.LogsEndpoint), "logs", "v1")
 	if err != nil {
 		return nil, err
 	}
+	if oce.endpoints, err = composeSignalEndpoints(oCfg, oCfg.Logs.endpoint(oCfg.LogsEndpoint), "logs", "v1", set.TelemetrySettings); err != nil {
+		return nil, err
+	}
+	// 只有转换后的日志会落盘
+	if oCfg.Spill.Directory != "" && oCfg.Conversion.Logs {
+		if oce.spill, err = jvm.NewSpillQueue(oCfg.Spill, set.ID, "logs", oce.sendPayload, set.TelemetrySettings); err != nil {
+			return nil, err
+		}
+	}
 
 	return exporterhelper.NewLogs(ctx, set, cfg,
 		oce.pushLogs,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
@@ -172,14 +271,19 @@ func createProfiles(
 	}
 	oCfg := cfg.(*Config)
 
-	oce.profilesURL, err = composeSignalURL(oCfg, "", "profiles", "v1development")
+	oce.signal = oCfg.Profiles
+	oce.profilesURL, err = composeSignalURL(oCfg, oCfg.Profiles.endpoint(""), "profiles", "v1development")
 	if err != nil {
 		return nil, err
 	}
+	if oce.endpoints, err = composeSignalEndpoints(oCfg, oCfg.Profiles.endpoint(""), "profiles", "v1development", set.TelemetrySettings); err != nil {
+		return nil, err
+	}
 
 	return xexporterhelper.NewProfilesExporter(ctx, set, cfg,
 		oce.pushProfiles,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
@@ -0,0 +1,313 @@
+package jvmhttpexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+const (
+	// FailoverStrategyPriority sends to the first healthy endpoint in the configured order.
+	FailoverStrategyPriority = "priority"
+	// FailoverStrategyRoundRobin spreads requests over the healthy endpoints in turn.
+	FailoverStrategyRoundRobin = "round_robin"
+
+	defaultFailoverFailureThreshold = 3
+	defaultFailoverProbeInterval    = 30 * time.Second
+
+	// 每个地址的请求结果
+	endpointOutcomeSuccess   = "success"
+	endpointOutcomeRejected  = "rejected"
+	endpointOutcomeThrottled = "throttled"
+	endpointOutcomeFailure   = "failure"
+)
+
+// errEndpointBusy 表示地址可用但暂时不能处理请求：429、带 Retry-After 的 503 或可重试的 ack
+var errEndpointBusy = errors.New("endpoint busy")
+
+// FailoverConfig defines a list of endpoints the signals are spread over or failed over between.
+type FailoverConfig struct {
+	// Endpoints are the base URLs used instead of endpoint. The signal path is appended as for
+	// endpoint. A signal with its own endpoint is only sent to that endpoint. Empty disables
+	// failover.
+	Endpoints []string `mapstructure:"endpoints"`
+
+	// Strategy is "priority" (default) or "round_robin". A request that fails with a retryable
+	// error is sent to the next healthy endpoint before it is retried.
+	Strategy string `mapstructure:"strategy"`
+
+	// FailureThreshold is the number of consecutive failed requests after which an endpoint is
+	// marked down. Rejected requests and requests the endpoint is too busy for (429, 503 with
+	// Retry-After, retryable ack codes) are not failures.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+
+	// ProbeInterval is how often endpoints marked down are probed with a request to the signal
+	// URL. An endpoint that answers with any status other than 502, 503 or 504 is marked up
+	// again. While all endpoints are down requests are sent to the endpoint that failed least
+	// recently.
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+
+	// ProbeMethod is the HTTP method of probe requests: "HEAD" (default), "GET" or "OPTIONS".
+	ProbeMethod string `mapstructure:"probe_method"`
+}
+
+func newDefaultFailoverConfig() FailoverConfig {
+	return FailoverConfig{
+		Strategy:         FailoverStrategyPriority,
+		FailureThreshold: defaultFailoverFailureThreshold,
+		ProbeInterval:    defaultFailoverProbeInterval,
+		ProbeMethod:      http.MethodHead,
+	}
+}
+
+// Validate checks the failover configuration.
+func (cfg *FailoverConfig) Validate() error {
+	if len(cfg.Endpoints) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(cfg.Endpoints))
+	for _, endpoint := range cfg.Endpoints {
+		if endpoint == "" {
+			return errors.New("endpoints must not contain an empty URL")
+		}
+		if _, err := url.Parse(endpoint); err != nil {
+			return fmt.Errorf("endpoint %q must be a valid URL", endpoint)
+		}
+		if seen[endpoint] {
+			return fmt.Errorf("endpoint %q is specified more than once", endpoint)
+		}
+		seen[endpoint] = true
+	}
+	switch cfg.Strategy {
+	case FailoverStrategyPriority, FailoverStrategyRoundRobin:
+	default:
+		return fmt.Errorf("invalid strategy %q", cfg.Strategy)
+	}
+	if cfg.FailureThreshold <= 0 {
+		return errors.New("failure_threshold must be positive")
+	}
+	if cfg.ProbeInterval <= 0 {
+		return errors.New("probe_interval must be positive")
+	}
+	switch cfg.ProbeMethod {
+	case http.MethodHead, http.MethodGet, http.MethodOptions:
+	default:
+		return fmt.Errorf("invalid probe_method %q", cfg.ProbeMethod)
+	}
+	return nil
+}
+
+// endpointState 是一个地址的健康状态
+type endpointState struct {
+	url string
+	// index 为地址在 failover.endpoints 中的位置
+	index int
+	// failures 为连续失败次数
+	failures int
+	// failedAt 为最近一次失败的序号，所有地址都下线时选择最早失败的地址
+	failedAt uint64
+	down     bool
+}
+
+// endpointPool 为一个信号在多个地址间选择发送地址，并根据请求结果标记地址上下线
+type endpointPool struct {
+	cfg       FailoverConfig
+	signal    string
+	logger    *zap.Logger
+	telemetry *endpointTelemetry
+
+	mu        sync.Mutex
+	endpoints []*endpointState
+	// next 为轮询的起始位置
+	next int
+	// failureSeq 为失败请求的序号
+	failureSeq uint64
+
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func newEndpointPool(cfg FailoverConfig, urls []string, signal string, set component.TelemetrySettings) (*endpointPool, error) {
+	telemetry, err := newEndpointTelemetry(set)
+	if err != nil {
+		return nil, err
+	}
+	pool := &endpointPool{
+		cfg:       cfg,
+		signal:    signal,
+		logger:    set.Logger,
+		telemetry: telemetry,
+	}
+	for i, u := range urls {
+		pool.endpoints = append(pool.endpoints, &endpointState{url: u, index: i})
+	}
+	return pool, nil
+}
+
+// Start 启动探测下线地址的后台循环，probe 返回 nil 表示地址已恢复
+func (p *endpointPool) Start(probe func(ctx context.Context, url string) error) {
+	// Shutdown 会清空 p.stop，后台循环使用局部变量
+	stop := make(chan struct{})
+	p.stop = stop
+	p.done = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.cfg.ProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.probe(ctx, probe)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown 停止探测循环
+func (p *endpointPool) Shutdown(ctx context.Context) error {
+	if p.stop == nil {
+		return nil
+	}
+	// 先清空 stop，等待超时后再次调用 Shutdown 不会重复关闭
+	close(p.stop)
+	p.stop = nil
+	p.cancel()
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// probe 探测所有下线的地址，恢复探测成功的地址
+func (p *endpointPool) probe(ctx context.Context, probe func(ctx context.Context, url string) error) {
+	for _, endpoint := range p.downEndpoints() {
+		if err := probe(ctx, endpoint.url); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			p.logger.Debug("Endpoint still down", zap.String("signal", p.signal), zap.String("url", endpoint.url), zap.Error(err))
+			continue
+		}
+		p.mu.Lock()
+		p.recoverLocked(endpoint)
+		p.mu.Unlock()
+	}
+}
+
+func (p *endpointPool) downEndpoints() []*endpointState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var down []*endpointState
+	for _, endpoint := range p.endpoints {
+		if endpoint.down {
+			down = append(down, endpoint)
+		}
+	}
+	return down
+}
+
+// send 依次向正常的地址发送，直到请求成功、被拒绝或所有地址都失败
+func (p *endpointPool) send(ctx context.Context, send func(url string) error) error {
+	candidates := p.candidates()
+	var errs []error
+	for _, endpoint := range candidates {
+		err := send(endpoint.url)
+		if ctx.Err() != nil {
+			// 请求被取消，不能说明地址的健康状态
+			return err
+		}
+		if p.result(endpoint, err) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// candidates 按策略的顺序返回本次请求的候选地址，下线的地址由探测循环恢复。
+// 所有地址都下线时返回最早失败的地址，探测失败时请求仍有机会送达
+func (p *endpointPool) candidates() []*endpointState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	start := 0
+	if p.cfg.Strategy == FailoverStrategyRoundRobin {
+		start = p.next
+		p.next = (p.next + 1) % len(p.endpoints)
+	}
+	candidates := make([]*endpointState, 0, len(p.endpoints))
+	for i := range p.endpoints {
+		endpoint := p.endpoints[(start+i)%len(p.endpoints)]
+		if !endpoint.down {
+			candidates = append(candidates, endpoint)
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates
+	}
+	oldest := p.endpoints[0]
+	for _, endpoint := range p.endpoints[1:] {
+		if endpoint.failedAt < oldest.failedAt {
+			oldest = endpoint
+		}
+	}
+	return []*endpointState{oldest}
+}
+
+// result 根据请求结果更新地址状态，返回 true 表示不再尝试其他地址
+func (p *endpointPool) result(endpoint *endpointState, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err == nil || consumererror.IsPermanent(err):
+		// 后端拒绝请求体时地址本身是可用的
+		outcome := endpointOutcomeSuccess
+		if err != nil {
+			outcome = endpointOutcomeRejected
+		}
+		p.telemetry.recordEndpointRequest(p.signal, endpoint.index, outcome)
+		p.recoverLocked(endpoint)
+		return true
+	case errors.Is(err, errEndpointBusy):
+		// 繁忙的地址仍然可用，交给下一个地址发送
+		p.telemetry.recordEndpointRequest(p.signal, endpoint.index, endpointOutcomeThrottled)
+		return false
+	default:
+		p.telemetry.recordEndpointRequest(p.signal, endpoint.index, endpointOutcomeFailure)
+		endpoint.failures++
+		p.failureSeq++
+		endpoint.failedAt = p.failureSeq
+		if !endpoint.down && endpoint.failures >= p.cfg.FailureThreshold {
+			endpoint.down = true
+			p.telemetry.recordEndpointDown(p.signal, 1)
+			p.logger.Warn("Endpoint marked down",
+				zap.String("signal", p.signal), zap.String("url", endpoint.url),
+				zap.Int("failures", endpoint.failures), zap.Error(err))
+		}
+		return false
+	}
+}
+
+// recoverLocked 重置地址的失败次数，下线的地址重新上线
+func (p *endpointPool) recoverLocked(endpoint *endpointState) {
+	endpoint.failures = 0
+	if endpoint.down {
+		endpoint.down = false
+		p.telemetry.recordEndpointDown(p.signal, -1)
+		p.logger.Info("Endpoint recovered", zap.String("signal", p.signal), zap.String("url", endpoint.url))
+	}
+}
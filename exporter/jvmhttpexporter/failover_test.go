@@ -0,0 +1,304 @@
+package jvmhttpexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+func newTestEndpointPool(t *testing.T, cfg FailoverConfig, urls ...string) *endpointPool {
+	pool, err := newEndpointPool(cfg, urls, "traces", component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestFailoverConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*FailoverConfig)
+		wantErr string
+	}{
+		{name: "disabled", modify: func(*FailoverConfig) {}},
+		{name: "disabled ignores strategy", modify: func(cfg *FailoverConfig) { cfg.Strategy = "random" }},
+		{name: "enabled", modify: func(cfg *FailoverConfig) { cfg.Endpoints = []string{"http://a:4318", "http://b:4318"} }},
+		{
+			name:    "empty endpoint",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints = []string{"http://a:4318", ""} },
+			wantErr: "endpoints must not contain an empty URL",
+		},
+		{
+			name:    "invalid endpoint",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints = []string{"http://a b:%zz"} },
+			wantErr: `endpoint "http://a b:%zz" must be a valid URL`,
+		},
+		{
+			name:    "duplicate endpoint",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints = []string{"http://a:4318", "http://a:4318"} },
+			wantErr: `endpoint "http://a:4318" is specified more than once`,
+		},
+		{
+			name:    "unknown strategy",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints, cfg.Strategy = []string{"http://a:4318"}, "random" },
+			wantErr: `invalid strategy "random"`,
+		},
+		{
+			name:    "no failure threshold",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints, cfg.FailureThreshold = []string{"http://a:4318"}, 0 },
+			wantErr: "failure_threshold must be positive",
+		},
+		{
+			name:    "no probe interval",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints, cfg.ProbeInterval = []string{"http://a:4318"}, 0 },
+			wantErr: "probe_interval must be positive",
+		},
+		{
+			name:    "probe method with a body",
+			modify:  func(cfg *FailoverConfig) { cfg.Endpoints, cfg.ProbeMethod = []string{"http://a:4318"}, http.MethodPost },
+			wantErr: `invalid probe_method "POST"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newDefaultFailoverConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEndpointPoolSend(t *testing.T) {
+	errUnavailable := errors.New("backend unavailable")
+	errBusy := fmt.Errorf("%w: 429 Too Many Requests", errEndpointBusy)
+	errRejected := consumererror.NewPermanent(errors.New("bad request"))
+	// round 是一次请求，fail 为各地址返回的错误，tried 为依次尝试的地址
+	type round struct {
+		fail    map[string]error
+		tried   []string
+		wantErr string
+	}
+	tests := []struct {
+		name      string
+		strategy  string
+		threshold int
+		rounds    []round
+	}{
+		{
+			name:   "priority sends to the first endpoint",
+			rounds: []round{{tried: []string{"a"}}, {tried: []string{"a"}}},
+		},
+		{
+			name:     "round robin rotates the endpoints",
+			strategy: FailoverStrategyRoundRobin,
+			rounds:   []round{{tried: []string{"a"}}, {tried: []string{"b"}}, {tried: []string{"c"}}, {tried: []string{"a"}}},
+		},
+		{
+			name: "failed requests go to the next endpoint",
+			rounds: []round{
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a", "b"}},
+				{tried: []string{"a"}},
+			},
+		},
+		{
+			name:      "endpoint is marked down at the failure threshold",
+			threshold: 2,
+			rounds: []round{
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a", "b"}},
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a", "b"}},
+				{tried: []string{"b"}},
+			},
+		},
+		{
+			name:      "success resets the failures",
+			threshold: 2,
+			rounds: []round{
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a", "b"}},
+				{tried: []string{"a"}},
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a", "b"}},
+				{tried: []string{"a"}},
+			},
+		},
+		{
+			name:      "busy endpoints are not marked down",
+			threshold: 1,
+			rounds: []round{
+				{fail: map[string]error{"a": errBusy}, tried: []string{"a", "b"}},
+				{tried: []string{"a"}},
+			},
+		},
+		{
+			name:      "rejected requests are not sent to other endpoints",
+			threshold: 1,
+			rounds: []round{
+				{fail: map[string]error{"a": errRejected}, tried: []string{"a"}, wantErr: "Permanent error: bad request"},
+				{tried: []string{"a"}},
+			},
+		},
+		{
+			name:      "all endpoints down",
+			threshold: 1,
+			rounds: []round{
+				{
+					fail:    map[string]error{"a": errUnavailable, "b": errUnavailable, "c": errUnavailable},
+					tried:   []string{"a", "b", "c"},
+					wantErr: "backend unavailable\nbackend unavailable\nbackend unavailable",
+				},
+				// 所有地址都下线时发送到最早失败的地址
+				{fail: map[string]error{"a": errUnavailable}, tried: []string{"a"}, wantErr: "backend unavailable"},
+				{tried: []string{"b"}},
+				// 发送成功的地址重新上线
+				{tried: []string{"b"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newDefaultFailoverConfig()
+			if tt.strategy != "" {
+				cfg.Strategy = tt.strategy
+			}
+			if tt.threshold > 0 {
+				cfg.FailureThreshold = tt.threshold
+			}
+			pool := newTestEndpointPool(t, cfg, "a", "b", "c")
+			for i, r := range tt.rounds {
+				var tried []string
+				err := pool.send(context.Background(), func(url string) error {
+					tried = append(tried, url)
+					return r.fail[url]
+				})
+				assert.Equal(t, r.tried, tried, "round %d", i)
+				if r.wantErr == "" {
+					assert.NoError(t, err, "round %d", i)
+				} else {
+					assert.EqualError(t, err, r.wantErr, "round %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestEndpointPoolProbe(t *testing.T) {
+	cfg := newDefaultFailoverConfig()
+	cfg.FailureThreshold = 1
+	pool := newTestEndpointPool(t, cfg, "a", "b", "c")
+	err := pool.send(context.Background(), func(url string) error {
+		if url == "c" {
+			return nil
+		}
+		return errors.New("backend unavailable")
+	})
+	require.NoError(t, err)
+
+	// 只探测下线的地址，探测失败的地址保持下线
+	var probed []string
+	pool.probe(context.Background(), func(_ context.Context, url string) error {
+		probed = append(probed, url)
+		if url == "a" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"a", "b"}, probed)
+
+	var tried []string
+	require.NoError(t, pool.send(context.Background(), func(url string) error {
+		tried = append(tried, url)
+		return nil
+	}))
+	assert.Equal(t, []string{"b"}, tried)
+}
+
+func TestEndpointPoolShutdown(t *testing.T) {
+	cfg := newDefaultFailoverConfig()
+	cfg.ProbeInterval = time.Millisecond
+	cfg.FailureThreshold = 1
+	pool := newTestEndpointPool(t, cfg, "a")
+	// 未启动时 Shutdown 直接返回
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	_ = pool.send(context.Background(), func(string) error { return errors.New("backend unavailable") })
+	pool.Start(func(context.Context, string) error { return nil })
+	assert.Eventually(t, func() bool { return len(pool.downEndpoints()) == 0 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, pool.Shutdown(context.Background()))
+	// 再次调用不会重复关闭
+	require.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "ok", method: http.MethodHead, statusCode: http.StatusOK},
+		{name: "not found", method: http.MethodHead, statusCode: http.StatusNotFound},
+		{name: "method not allowed", method: http.MethodHead, statusCode: http.StatusMethodNotAllowed},
+		{name: "not implemented", method: http.MethodGet, statusCode: http.StatusNotImplemented},
+		{name: "bad gateway", method: http.MethodHead, statusCode: http.StatusBadGateway, wantErr: true},
+		{name: "service unavailable", method: http.MethodOptions, statusCode: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = server.URL
+			cfg.Failover.ProbeMethod = tt.method
+			e := newTestExporter(t, cfg)
+
+			err := e.probeEndpoint(context.Background(), server.URL+"/v1/traces")
+			assert.Equal(t, tt.method, method)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOfflineEventsFailOver(t *testing.T) {
+	var paths []string
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer up.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Failover.Endpoints = []string{down.URL, up.URL + "/"}
+	cfg.Offline.Timeout = time.Minute
+	cfg.OfflineEndpoint = "/v1/offline"
+	require.NoError(t, cfg.Validate())
+	e := newTestExporter(t, cfg)
+	e.offlineURL = cfg.OfflineEndpoint
+	var err error
+	e.offlineEndpoints, err = composeOfflineEndpoints(cfg, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	require.NoError(t, e.sendPayload(context.Background(), payloadKindOffline, []byte(`{}`)))
+	assert.Equal(t, []string{"/v1/offline"}, paths)
+}
@@ -0,0 +1,51 @@
+package jvmhttpexporter
+
+import (
+	"fmt"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+)
+
+const (
+	defaultMasterIPTemplate = "${host.ip|k8s.pod.ip|net.host.ip:-127.0.0.1}"
+	defaultApmLang          = "java"
+)
+
+// IdentityConfig defines how agentId, multiAgentId and masterIp are derived from resource attributes.
+// MasterIP uses the same template syntax as agent_id and multi_agent_id.
+type IdentityConfig struct {
+	jvm.IdentityConfig `mapstructure:",squash"`
+
+	// MasterIP is the template for masterIp.
+	MasterIP string `mapstructure:"master_ip"`
+
+	// ApmLang is reported as apm-lang.
+	ApmLang string `mapstructure:"apm_lang"`
+}
+
+func newDefaultIdentityConfig() IdentityConfig {
+	return IdentityConfig{
+		IdentityConfig: jvm.NewDefaultIdentityConfig(),
+		MasterIP:       defaultMasterIPTemplate,
+		ApmLang:        defaultApmLang,
+	}
+}
+
+// identity 是编译后的身份模板
+type identity struct {
+	*jvm.Identity
+	masterIP *jvm.Template
+	apmLang  string
+}
+
+func newIdentity(cfg IdentityConfig) (*identity, error) {
+	id, err := jvm.NewIdentity(cfg.IdentityConfig)
+	if err != nil {
+		return nil, err
+	}
+	masterIP, err := jvm.ParseTemplate(cfg.MasterIP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master_ip template: %w", err)
+	}
+	return &identity{Identity: id, masterIP: masterIP, apmLang: cfg.ApmLang}, nil
+}
@@ -0,0 +1,20 @@
+package jvmhttpexporter
+
+import (
+	"encoding/json"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"github.com/Liuxiaoxxz/third-party/grpc/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+// marshalManagementData 按 encoding 编码 JVM 指标的请求体：json 为 Data 信封，
+// proto 为与 jvmxexporter 相同的 metrics.ExportRequest，信封中的 logType 和 masterIp 不发送
+func (c *metricConverter) marshalManagementData(data *Data, threadDetails []jvm.ThreadDetail) ([]byte, error) {
+	if c.encoding != EncodingProto {
+		return json.Marshal(data)
+	}
+	return proto.Marshal(&metrics.ExportRequest{
+		Orig: jvm.ManagementMessageToProto(data.LogMessage.JManagementMessage, threadDetails),
+	})
+}
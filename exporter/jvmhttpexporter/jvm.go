@@ -10,8 +10,12 @@ import (
 	"net/url"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"github.com/Liuxiaoxxz/third-party/grpc/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/protobuf/proto"
@@ -19,7 +23,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -36,6 +39,12 @@ const (
 
 	jsonContentType     = "application/json"
 	protobufContentType = "application/x-protobuf"
+
+	// 转换后请求体的类型，落盘回放时据此选择发送地址
+	payloadKindTraces  = "traces"
+	payloadKindMetrics = "metrics"
+	payloadKindLogs    = "logs"
+	payloadKindOffline = "offline"
 )
 
 type baseExporter struct {
@@ -48,6 +57,28 @@ type baseExporter struct {
 	profilesURL string
 	logger      *zap.Logger
 	settings    component.TelemetrySettings
+	converter   *metricConverter
+	threadDumps *jvm.ThreadDumpStore
+	// releaseThreadDumps 保证重复调用 shutdown 时只释放一次
+	releaseThreadDumps sync.Once
+	traces             *traceConverter
+	logs               *logConverter
+	// assembler 不为空时指标先按 JVM 缓存，再按周期发送
+	assembler *jvm.Assembler
+	// offline 不为空时跟踪各 JVM 的最后上报时间
+	offline *jvm.OfflineTracker
+	// offlineURL 为空时离线只通过指标消息上报
+	offlineURL string
+	// offlineEndpoints 不为空时离线事件的发送地址由 failover 选择
+	offlineEndpoints *endpointPool
+	// spill 不为空时发送失败的请求体落盘并在后端恢复后回放
+	spill *jvm.SpillQueue
+	// ack 不为空时按 JVM 后端的确认格式解析转换后请求体的响应
+	ack partialSuccessHandler
+	// signal 为当前信号的传输覆盖配置
+	signal SignalConfig
+	// endpoints 不为空时信号的发送地址由 failover 选择，不使用 tracesURL 等地址
+	endpoints *endpointPool
 	// Default user-agent header.
 	userAgent string
 }
@@ -64,15 +95,40 @@ func newExporter(cfg component.Config, set exporter.Settings) (*baseExporter, er
 		}
 	}
 
+	// 日志中的线程详情需要附加到同一导出器的指标中，shutdown 时释放
+	threadDumps := jvm.AcquireThreadDumpStore(set.ID)
+	converter, err := newMetricConverter(oCfg, threadDumps, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	traces, err := newTraceConverter(oCfg, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := newLogConverter(oCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	userAgent := fmt.Sprintf("%s/%s (%s/%s)",
 		set.BuildInfo.Description, set.BuildInfo.Version, runtime.GOOS, runtime.GOARCH)
 
+	var ack partialSuccessHandler
+	if oCfg.Ack.Format == AckFormatJVM {
+		ack = newAckHandler(oCfg.Ack, set.Logger)
+	}
+
 	// client construction is deferred to start
 	return &baseExporter{
-		config:    oCfg,
-		logger:    set.Logger,
-		userAgent: userAgent,
-		settings:  set.TelemetrySettings,
+		config:      oCfg,
+		logger:      set.Logger,
+		userAgent:   userAgent,
+		settings:    set.TelemetrySettings,
+		converter:   converter,
+		threadDumps: threadDumps,
+		traces:      traces,
+		logs:        logs,
+		ack:         ack,
 	}, nil
 }
 
@@ -80,69 +136,188 @@ func newExporter(cfg component.Config, set exporter.Settings) (*baseExporter, er
 // is the only place we get hold of Extensions which are required to construct auth round tripper.
 func (e *baseExporter) start(ctx context.Context, host component.Host) error {
 	e.logger.Info("Starting JVM HTTP exporter ......")
-	client, err := e.config.ClientConfig.ToClient(ctx, host, e.settings)
+	clientConfig := e.signal.clientConfig(e.config.ClientConfig)
+	client, err := clientConfig.ToClient(ctx, host, e.settings)
 	if err != nil {
 		return err
 	}
 	e.client = client
+	if e.spill != nil {
+		if err = e.spill.Start(); err != nil {
+			return err
+		}
+	}
+	if e.assembler != nil {
+		e.assembler.Start()
+	}
+	if e.offline != nil {
+		e.offline.Start()
+	}
+	if e.endpoints != nil {
+		e.endpoints.Start(e.probeEndpoint)
+	}
+	if e.offlineEndpoints != nil {
+		e.offlineEndpoints.Start(e.probeEndpoint)
+	}
 	e.logger.Info("JVM HTTP exporter client successfully started")
 	return nil
 }
 
+// shutdown stops offline detection, sends the last assembled snapshot, if any, and stops replaying
+// spilled payloads and probing failover endpoints.
+func (e *baseExporter) shutdown(ctx context.Context) error {
+	var err error
+	if e.offline != nil {
+		err = e.offline.Shutdown(ctx)
+	}
+	if e.assembler != nil {
+		err = errors.Join(err, e.assembler.Shutdown(ctx))
+	}
+	if e.spill != nil {
+		err = errors.Join(err, e.spill.Shutdown(ctx))
+	}
+	if e.endpoints != nil {
+		err = errors.Join(err, e.endpoints.Shutdown(ctx))
+	}
+	if e.offlineEndpoints != nil {
+		err = errors.Join(err, e.offlineEndpoints.Shutdown(ctx))
+	}
+	e.releaseThreadDumps.Do(e.threadDumps.Release)
+	return err
+}
+
 func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
+	if !e.config.Conversion.Traces {
+		return e.forwardTraces(ctx, td)
+	}
+	requests, err := e.traces.traceTransform(td)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	if len(requests) == 0 {
+		e.logger.Debug("No transactions found in batch, skipping export")
+		return nil
+	}
+	return e.deliverAll(ctx, payloadKindTraces, requests)
+}
+
+// forwardTraces sends the spans as an OTLP export request.
+func (e *baseExporter) forwardTraces(ctx context.Context, td ptrace.Traces) error {
 	tr := ptraceotlp.NewExportRequestFromTraces(td)
 
 	var err error
 	var request []byte
-	switch e.config.Encoding {
+	switch e.encoding() {
 	case EncodingJSON:
 		request, err = tr.MarshalJSON()
 	case EncodingProto:
 		request, err = tr.MarshalProto()
 	default:
-		err = fmt.Errorf("invalid encoding: %s", e.config.Encoding)
+		err = fmt.Errorf("invalid encoding: %s", e.encoding())
 	}
 
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
 
-	return e.export(ctx, e.tracesURL, request, e.tracesPartialSuccessHandler)
+	return e.exportSignal(ctx, e.tracesURL, request, e.contentType(), e.tracesPartialSuccessHandler)
 }
 
 func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
-	//var err error
-	//// TODO 实现otlp格式的转化
-	//request_bck, err := metricTransform(ctx, md)
-	//e.logger.Info("JVM HTTP exporter metric transform request successfully started, request Body: " + string(request_bck))
-	//if err != nil {
-	//	return consumererror.NewPermanent(err)
-	//}
-	//return e.export(ctx, e.metricsURL, request_bck, e.metricsPartialSuccessHandler)
-
-	e.logger.Info("received a metric:{}" + md.ResourceMetrics().At(0).ScopeMetrics().At(0).Scope().Name())
+	if e.assembler != nil {
+		e.assembler.Add(md, time.Now())
+		return nil
+	}
+	return e.sendMetrics(ctx, md)
+}
+
+// sendMetrics converts the metrics and sends one envelope per JVM instance.
+func (e *baseExporter) sendMetrics(ctx context.Context, md pmetric.Metrics) error {
+	requests, err := e.converter.metricTransform(ctx, md)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	if len(requests) == 0 {
+		e.logger.Debug("No JVM metrics found in batch, skipping export")
+		return nil
+	}
+	return e.deliverAll(ctx, payloadKindMetrics, requests)
+}
+
+// flushMetrics converts an assembled snapshot and sends it. The snapshot does not pass through the
+// sending queue, so each converted request is retried as configured by retry_on_failure. Retrying
+// the converted requests instead of the snapshot keeps delta metrics from being counted twice.
+func (e *baseExporter) flushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	requests, err := e.converter.metricTransform(ctx, md)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	for _, send := range e.deliveries(ctx, payloadKindMetrics, requests) {
+		if err = jvm.Retry(ctx, e.config.RetryConfig, send); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// sendOffline sends the final offline message of each JVM and, when configured, an offline event.
+func (e *baseExporter) sendOffline(ctx context.Context, agents []jvm.OfflineAgent) error {
+	now := time.Now()
+	requests, err := e.converter.offlineTransform(agents, now)
+	if err != nil {
+		return err
+	}
+	if err = e.deliverAll(ctx, payloadKindMetrics, requests); err != nil {
+		return err
+	}
+	if e.offlineURL == "" {
+		return nil
+	}
+	events, err := e.converter.offlineEvents(agents, now)
+	if err != nil {
+		return err
+	}
+	return e.deliverAll(ctx, payloadKindOffline, events)
+}
+
 func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
+	if collected := e.threadDumps.Consume(ld, time.Now()); collected > 0 {
+		e.logger.Debug("Collected JVM thread details from logs", zap.Int("threads", collected))
+	}
+	if !e.config.Conversion.Logs {
+		return e.forwardLogs(ctx, ld)
+	}
+	requests, err := e.logs.logTransform(ld)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	if len(requests) == 0 {
+		e.logger.Debug("No log records found in batch, skipping export")
+		return nil
+	}
+	return e.deliverAll(ctx, payloadKindLogs, requests)
+}
+
+// forwardLogs sends the log records as an OTLP export request.
+func (e *baseExporter) forwardLogs(ctx context.Context, ld plog.Logs) error {
 	tr := plogotlp.NewExportRequestFromLogs(ld)
 
 	var err error
 	var request []byte
-	switch e.config.Encoding {
+	switch e.encoding() {
 	case EncodingJSON:
 		request, err = tr.MarshalJSON()
 	case EncodingProto:
 		request, err = tr.MarshalProto()
 	default:
-		err = fmt.Errorf("invalid encoding: %s", e.config.Encoding)
+		err = fmt.Errorf("invalid encoding: %s", e.encoding())
 	}
 
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
 
-	return e.export(ctx, e.logsURL, request, e.logsPartialSuccessHandler)
+	return e.exportSignal(ctx, e.logsURL, request, e.contentType(), e.logsPartialSuccessHandler)
 }
 
 func (e *baseExporter) pushProfiles(ctx context.Context, td pprofile.Profiles) error {
@@ -150,47 +325,208 @@ func (e *baseExporter) pushProfiles(ctx context.Context, td pprofile.Profiles) e
 
 	var err error
 	var request []byte
-	switch e.config.Encoding {
+	switch e.encoding() {
 	case EncodingJSON:
 		request, err = tr.MarshalJSON()
 	case EncodingProto:
 		request, err = tr.MarshalProto()
 	default:
-		err = fmt.Errorf("invalid encoding: %s", e.config.Encoding)
+		err = fmt.Errorf("invalid encoding: %s", e.encoding())
 	}
 
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
 
-	return e.export(ctx, e.profilesURL, request, e.profilesPartialSuccessHandler)
+	return e.exportSignal(ctx, e.profilesURL, request, e.contentType(), e.profilesPartialSuccessHandler)
 }
 
-func (e *baseExporter) export(ctx context.Context, url string, request []byte, partialSuccessHandler partialSuccessHandler) error {
+// deliverAll sends the envelopes of one export. Without batching each envelope is sent as its own
+// request so that the backend never sees merged data.
+func (e *baseExporter) deliverAll(ctx context.Context, kind string, requests [][]byte) error {
+	for _, send := range e.deliveries(ctx, kind, requests) {
+		if err := send(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveries 返回一次导出中各个请求的发送函数，按顺序调用
+func (e *baseExporter) deliveries(ctx context.Context, kind string, requests [][]byte) []func() error {
+	var sends []func() error
+	// protobuf 请求体不能合并
+	if !e.config.Batching.enabled() || e.payloadContentType(kind) == protobufContentType {
+		for _, request := range requests {
+			sends = append(sends, func() error { return e.deliver(ctx, kind, request) })
+		}
+		return sends
+	}
+	for _, batch := range e.config.Batching.batches(requests) {
+		sends = append(sends, func() error { return e.deliverBatch(ctx, kind, batch) })
+	}
+	return sends
+}
+
+// deliverBatch sends a batch of envelopes and splits it in half when the backend rejects it as
+// too large. With spilling enabled a batch that fails with a retryable error is stored as one
+// single envelope batch per envelope, so that replaying it is never rejected as too large.
+func (e *baseExporter) deliverBatch(ctx context.Context, kind string, batch [][]byte) error {
+	if e.spill != nil && e.spill.Pending() > 0 {
+		return e.spillBatch(kind, batch)
+	}
+	err := e.sendPayload(ctx, kind, e.config.Batching.encode(batch))
+	switch {
+	case err == nil:
+		return nil
+	case len(batch) >= 2 && errors.Is(err, errPayloadTooLarge):
+		e.logger.Debug("Batch rejected as too large, splitting it", zap.String("kind", kind), zap.Int("items", len(batch)))
+		half := len(batch) / 2
+		if err = e.deliverBatch(ctx, kind, batch[:half]); err != nil {
+			return err
+		}
+		return e.deliverBatch(ctx, kind, batch[half:])
+	case e.spill == nil || consumererror.IsPermanent(err):
+		return err
+	}
+	e.logger.Debug("Failed to send batch, spilling it to disk", zap.String("kind", kind), zap.Int("items", len(batch)), zap.Error(err))
+	return e.spillBatch(kind, batch)
+}
+
+// spillBatch 将批次中的请求体逐个编码后按顺序落盘
+func (e *baseExporter) spillBatch(kind string, batch [][]byte) error {
+	now := time.Now()
+	for _, item := range batch {
+		if err := e.spill.Put(kind, e.config.Batching.encode([][]byte{item}), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends a converted payload. With spilling enabled a payload that fails with a retryable
+// error is stored on disk and replayed later, and while stored payloads are pending new ones are
+// queued behind them so that the backend receives them in order.
+func (e *baseExporter) deliver(ctx context.Context, kind string, request []byte) error {
+	if e.spill == nil {
+		return e.sendPayload(ctx, kind, request)
+	}
+	if e.spill.Pending() == 0 {
+		err := e.sendPayload(ctx, kind, request)
+		if err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+		e.logger.Debug("Failed to send payload, spilling it to disk", zap.String("kind", kind), zap.Error(err))
+	}
+	return e.spill.Put(kind, request, time.Now())
+}
+
+// sendPayload sends a converted payload to the URL of its kind.
+func (e *baseExporter) sendPayload(ctx context.Context, kind string, request []byte) error {
+	switch kind {
+	case payloadKindTraces:
+		return e.exportSignal(ctx, e.tracesURL, request, e.payloadContentType(kind), e.payloadHandler(e.tracesPartialSuccessHandler))
+	case payloadKindMetrics:
+		return e.exportSignal(ctx, e.metricsURL, request, e.payloadContentType(kind), e.payloadHandler(e.managementPartialSuccessHandler))
+	case payloadKindLogs:
+		return e.exportSignal(ctx, e.logsURL, request, e.payloadContentType(kind), e.payloadHandler(e.logsPartialSuccessHandler))
+	case payloadKindOffline:
+		// 落盘后 offline_endpoint 可能已从配置中移除
+		if e.offlineURL != "" {
+			return e.exportEndpoints(ctx, e.offlineEndpoints, e.offlineURL, request, e.payloadContentType(kind), e.payloadHandler(e.metricsPartialSuccessHandler))
+		}
+	}
+	return consumererror.NewPermanent(fmt.Errorf("no URL configured for %s payloads", kind))
+}
+
+// payloadHandler returns the handler of responses to converted payloads. When the JVM backend ack
+// is configured it interprets JSON responses, other responses are left to handler.
+func (e *baseExporter) payloadHandler(handler partialSuccessHandler) partialSuccessHandler {
+	if e.ack == nil {
+		return handler
+	}
+	return func(body []byte, contentType string) error {
+		if strings.HasPrefix(contentType, jsonContentType) {
+			return e.ack(body, contentType)
+		}
+		return handler(body, contentType)
+	}
+}
+
+// payloadContentType returns the Content-Type of converted payloads of the given kind. JVM metrics
+// are sent as metrics.ExportRequest when the encoding is proto, everything else is JSON.
+func (e *baseExporter) payloadContentType(kind string) string {
+	if kind == payloadKindMetrics && e.encoding() == EncodingProto {
+		return protobufContentType
+	}
+	return e.config.Batching.contentType()
+}
+
+// encoding returns the encoding of the signal, which may override the top level encoding.
+func (e *baseExporter) encoding() EncodingType {
+	return e.signal.encoding(e.config.Encoding)
+}
+
+// contentType returns the Content-Type matching the configured OTLP encoding.
+func (e *baseExporter) contentType() string {
+	if e.encoding() == EncodingProto {
+		return protobufContentType
+	}
+	return jsonContentType
+}
+
+// probeEndpoint sends a probe request to a failover endpoint that is marked down. Gateways often
+// answer probes with 404, 405 or 501, so any response other than 502, 503 or 504 means the
+// endpoint is reachable again.
+func (e *baseExporter) probeEndpoint(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, e.config.Failover.ProbeMethod, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Errorf("probe to %s responded with HTTP Status Code %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// exportSignal sends a request of the exporter's signal. With failover endpoints the request is sent
+// to the endpoints chosen by the pool instead of url.
+func (e *baseExporter) exportSignal(ctx context.Context, url string, request []byte, contentType string, partialSuccessHandler partialSuccessHandler) error {
+	return e.exportEndpoints(ctx, e.endpoints, url, request, contentType, partialSuccessHandler)
+}
+
+// exportEndpoints sends a request to the endpoints chosen by endpoints, or to url when endpoints is nil.
+func (e *baseExporter) exportEndpoints(ctx context.Context, endpoints *endpointPool, url string, request []byte, contentType string, partialSuccessHandler partialSuccessHandler) error {
+	if endpoints == nil {
+		return e.export(ctx, url, request, contentType, partialSuccessHandler)
+	}
+	return endpoints.send(ctx, func(url string) error {
+		return e.export(ctx, url, request, contentType, partialSuccessHandler)
+	})
+}
+
+func (e *baseExporter) export(ctx context.Context, url string, request []byte, contentType string, partialSuccessHandler partialSuccessHandler) error {
 	e.logger.Debug("Preparing to make HTTP request", zap.String("url", url))
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(request))
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
 
-	switch e.config.Encoding {
-	case EncodingJSON:
-		req.Header.Set("Content-Type", jsonContentType)
-	case EncodingProto:
-		req.Header.Set("Content-Type", protobufContentType)
-	default:
-		return fmt.Errorf("invalid encoding: %s", e.config.Encoding)
-	}
-
-	// 打印 exporter 的 request
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", e.userAgent)
-	e.logger.Info("HTTP Request",
-		zap.String("Method", req.Method),
-		zap.String("URL", req.URL.String()),
-		zap.Any("Headers", req.Header),
-	)
-	requestCopy := string(request)
-	e.logger.Info("Request Body: " + requestCopy)
+	// protobuf 请求体不是可读文本，只记录 JSON 请求体
+	if contentType != protobufContentType {
+		if ce := e.logger.Check(zap.DebugLevel, "Request Body"); ce != nil {
+			ce.Write(zap.ByteString("body", request))
+		}
+	}
 
 	resp, err := e.client.Do(req)
 	if err != nil {
@@ -224,6 +560,10 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 	formattedErr = NewStatusFromMsgAndHTTPCode(errString, resp.StatusCode).Err()
 
 	if !isRetryableStatusCode(resp.StatusCode) {
+		// 批量请求据此拆分后重发
+		if resp.StatusCode == http.StatusRequestEntityTooLarge {
+			formattedErr = fmt.Errorf("%w: %w", errPayloadTooLarge, formattedErr)
+		}
 		return consumererror.NewPermanent(formattedErr)
 	}
 
@@ -233,6 +573,10 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 	if isThrottleError {
 		// Use Values to check if the header is present, and if present even is it is empty return ThrottleRetry.
 		values := resp.Header.Values(headerRetryAfter)
+		// 限流或给出 Retry-After 的地址仍然可用，failover 时不计为失败
+		if resp.StatusCode == http.StatusTooManyRequests || len(values) > 0 {
+			formattedErr = fmt.Errorf("%w: %w", errEndpointBusy, formattedErr)
+		}
 		if len(values) == 0 {
 			return formattedErr
 		}
@@ -243,10 +587,10 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 		//
 		// First try to parse delay-seconds, since that is what the receiver will send.
 		if seconds, err := strconv.Atoi(values[0]); err == nil {
-			return exporterhelper.NewThrottleRetry(formattedErr, time.Duration(seconds)*time.Second)
+			return jvm.NewThrottleRetry(formattedErr, time.Duration(seconds)*time.Second)
 		}
 		if date, err := time.Parse(time.RFC1123, values[0]); err == nil {
-			return exporterhelper.NewThrottleRetry(formattedErr, time.Until(date))
+			return jvm.NewThrottleRetry(formattedErr, time.Until(date))
 		}
 	}
 	return formattedErr
@@ -397,6 +741,22 @@ func (e *baseExporter) metricsPartialSuccessHandler(protoBytes []byte, contentTy
 	return nil
 }
 
+// managementPartialSuccessHandler checks the response to JVM metrics. A protobuf response is a
+// metrics.ExportResponse whose state must not be zero, as for the gRPC transport.
+func (e *baseExporter) managementPartialSuccessHandler(protoBytes []byte, contentType string) error {
+	if protoBytes == nil || contentType != protobufContentType {
+		return e.metricsPartialSuccessHandler(protoBytes, contentType)
+	}
+	exportResponse := &metrics.ExportResponse{}
+	if err := proto.Unmarshal(protoBytes, exportResponse); err != nil {
+		return fmt.Errorf("error parsing protobuf response: %w", err)
+	}
+	if exportResponse.GetState() == 0 {
+		return errors.New("metrics state is zero")
+	}
+	return nil
+}
+
 func (e *baseExporter) logsPartialSuccessHandler(protoBytes []byte, contentType string) error {
 	if protoBytes == nil {
 		return nil
@@ -0,0 +1,190 @@
+package jvmhttpexporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Liuxiaoxxz/third-party/grpc/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestExporter 返回直接使用 http.DefaultClient 发送的导出器
+func newTestExporter(t *testing.T, cfg *Config) *baseExporter {
+	set := exporter.Settings{
+		ID:                component.MustNewID("jvmhttp"),
+		TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()},
+		BuildInfo:         component.NewDefaultBuildInfo(),
+	}
+	e, err := newExporter(cfg, set)
+	require.NoError(t, err)
+	e.client = http.DefaultClient
+	t.Cleanup(func() { require.NoError(t, e.shutdown(context.Background())) })
+	return e
+}
+
+// newTestJVMMetrics 返回一个 JVM 的运行时指标
+func newTestJVMMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "bookdemo")
+	rm.Resource().Attributes().PutInt("process.pid", 4242)
+	rm.Resource().Attributes().PutStr("host.ip", "192.168.136.105")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("io.opentelemetry.runtime-telemetry-java17")
+
+	used := sm.Metrics().AppendEmpty()
+	used.SetName("jvm.memory.used")
+	used.SetEmptySum()
+	dp := used.Sum().DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("jvm.memory.pool.name", "G1 Eden Space")
+	dp.SetIntValue(1024)
+
+	threads := sm.Metrics().AppendEmpty()
+	threads.SetName("jvm.thread.count")
+	threads.SetEmptySum()
+	dp = threads.Sum().DataPoints().AppendEmpty()
+	dp.Attributes().PutBool("jvm.thread.daemon", true)
+	dp.SetIntValue(12)
+	dp = threads.Sum().DataPoints().AppendEmpty()
+	dp.Attributes().PutBool("jvm.thread.daemon", false)
+	dp.SetIntValue(8)
+	return md
+}
+
+func TestPushMetrics(t *testing.T) {
+	var path, contentType string
+	var data Data
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &data))
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Compression = "none"
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL + "/v1/metrics"
+
+	require.NoError(t, e.pushMetrics(context.Background(), newTestJVMMetrics()))
+	assert.Equal(t, "/v1/metrics", path)
+	assert.Equal(t, jsonContentType, contentType)
+	assert.Equal(t, "JavaManagementData", data.LogType)
+	assert.Equal(t, "192.168.136.105", data.MasterIp)
+	require.NotNil(t, data.LogMessage)
+	require.NotNil(t, data.LogMessage.JManagementMessage)
+	message := data.LogMessage.JManagementMessage
+	assert.Equal(t, "bookdemo", message.AppName)
+	assert.Equal(t, "4242", message.Pid)
+	assert.Equal(t, int64(1024), message.MemoryPool.MemoryUsages["G1EdenSpace"].Used)
+	assert.Equal(t, int64(20), message.Thread.ThreadCount)
+	assert.Equal(t, int64(12), message.Thread.DeamonThreadCount)
+}
+
+func TestPushMetricsWithoutJVMMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL + "/v1/metrics"
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Scope().SetName("io.opentelemetry.http")
+	assert.NoError(t, e.pushMetrics(context.Background(), md))
+}
+
+func TestPushMetricsConversionFailureIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL + "/v1/metrics"
+
+	// JSON 不能编码 NaN
+	md := newTestJVMMetrics()
+	cpu := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().AppendEmpty()
+	cpu.SetName("jvm.cpu.recent_utilization")
+	cpu.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(math.NaN())
+
+	err := e.pushMetrics(context.Background(), md)
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
+func TestPushMetricsProto(t *testing.T) {
+	var contentType string
+	request := &metrics.ExportRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, proto.Unmarshal(body, request))
+		response, err := proto.Marshal(&metrics.ExportResponse{State: 1})
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", protobufContentType)
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Compression = "none"
+	cfg.Encoding = EncodingProto
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL + "/v1/metrics"
+	e.converter.encoding = EncodingProto
+
+	require.NoError(t, e.pushMetrics(context.Background(), newTestJVMMetrics()))
+	assert.Equal(t, protobufContentType, contentType)
+	orig := request.GetOrig()
+	require.NotNil(t, orig)
+	assert.Equal(t, "bookdemo", orig.AppName)
+	assert.Equal(t, "4242", orig.Pid)
+	assert.Equal(t, int64(1024), orig.MemoryPool.MemoryUsages["G1EdenSpace"].Used)
+	assert.Equal(t, int64(20), orig.Thread.ThreadCount)
+}
+
+func TestPushMetricsProtoZeroState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		request := &metrics.ExportRequest{}
+		assert.NoError(t, proto.Unmarshal(body, request))
+		response, err := proto.Marshal(&metrics.ExportResponse{Orig: request.GetOrig()})
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", protobufContentType)
+		_, _ = w.Write(response)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Encoding = EncodingProto
+	e := newTestExporter(t, cfg)
+	e.metricsURL = server.URL + "/v1/metrics"
+	e.converter.encoding = EncodingProto
+
+	assert.EqualError(t, e.pushMetrics(context.Background(), newTestJVMMetrics()), "metrics state is zero")
+}
@@ -0,0 +1,202 @@
+package jvmhttpexporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	EXCEPTION_TYPE       = "exception.type"
+	EXCEPTION_STACKTRACE = "exception.stacktrace"
+	CODE_NAMESPACE       = "code.namespace"
+
+	logLevelTrace = "TRACE"
+	logLevelDebug = "DEBUG"
+	logLevelInfo  = "INFO"
+	logLevelWarn  = "WARN"
+	logLevelError = "ERROR"
+	logLevelFatal = "FATAL"
+)
+
+// LogEntry 对应一条日志记录
+type LogEntry struct {
+	AgentId      string        `json:"agentId"`
+	MultiAgentId string        `json:"multiAgentId"`
+	AppName      string        `json:"appName"`
+	Timestamp    string        `json:"timestamp"`
+	Level        string        `json:"level"`
+	Logger       string        `json:"logger"`
+	Thread       string        `json:"thread"`
+	Message      string        `json:"message"`
+	TraceId      string        `json:"traceId"`
+	SpanId       string        `json:"spanId"`
+	Exception    *LogException `json:"exception,omitempty"`
+}
+
+type LogException struct {
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+	StackTrace string `json:"stackTrace"`
+}
+
+// logConverter 负责将 OTLP 日志转换为内部日志格式
+type logConverter struct {
+	identity          *identity
+	timeFormat        string
+	logType           string
+	exceptionsLogType string
+}
+
+func newLogConverter(cfg *Config) (*logConverter, error) {
+	id, err := newIdentity(cfg.Identity)
+	if err != nil {
+		return nil, err
+	}
+	exceptionsLogType := cfg.LogTypes.Exceptions
+	if exceptionsLogType == "" {
+		exceptionsLogType = cfg.LogTypes.Logs
+	}
+	return &logConverter{
+		identity:          id,
+		timeFormat:        cfg.TimestampFormat,
+		logType:           cfg.LogTypes.Logs,
+		exceptionsLogType: exceptionsLogType,
+	}, nil
+}
+
+// logTransform 将 OTLP 日志按 JVM 实例和 logType 分组转换为内部格式，每组一个请求体。
+// 线程详情事件只用于指标，不作为日志上报
+func (c *logConverter) logTransform(ld plog.Logs) ([][]byte, error) {
+	// 同一 JVM 的日志按 logType 分组
+	type logGroup struct {
+		key     string
+		logType string
+	}
+	groups := make(map[logGroup]*Data)
+	var keys []logGroup
+
+	resourceLogs := ld.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		resourceLog := resourceLogs.At(i)
+		resourceAttributes := resourceLog.Resource().Attributes()
+		key := jvm.ResourceKey(resourceAttributes)
+		agentID, multiAgentID := c.identity.AgentIDs(resourceAttributes, key)
+		appName := stringAttribute(resourceAttributes, jvm.SERVICE_NAME)
+		masterIP := c.identity.masterIP.Render(resourceAttributes)
+
+		scopeLogs := resourceLog.ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			scopeLog := scopeLogs.At(j)
+			logRecords := scopeLog.LogRecords()
+			for k := 0; k < logRecords.Len(); k++ {
+				logRecord := logRecords.At(k)
+				if jvm.IsThreadDumpEvent(logRecord) {
+					continue
+				}
+				entry := c.newLogEntry(scopeLog.Scope().Name(), logRecord)
+				entry.AgentId, entry.MultiAgentId, entry.AppName = agentID, multiAgentID, appName
+
+				logType := c.logType
+				if entry.Exception != nil {
+					logType = c.exceptionsLogType
+				}
+				groupKey := logGroup{key: key, logType: logType}
+				data, ok := groups[groupKey]
+				if !ok {
+					data = &Data{
+						LogMessage: &LogMessage{ApmLang: c.identity.apmLang},
+						LogType:    logType,
+						MasterIp:   masterIP,
+					}
+					groups[groupKey] = data
+					keys = append(keys, groupKey)
+				}
+				data.LogMessage.Logs = append(data.LogMessage.Logs, entry)
+			}
+		}
+	}
+
+	requests := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		jsonBytes, err := json.Marshal(groups[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s logs of %q: %w", key.logType, key.key, err)
+		}
+		requests = append(requests, jsonBytes)
+	}
+	return requests, nil
+}
+
+func (c *logConverter) newLogEntry(scopeName string, logRecord plog.LogRecord) *LogEntry {
+	attributes := logRecord.Attributes()
+	timestamp := logRecord.Timestamp()
+	if timestamp == 0 {
+		timestamp = logRecord.ObservedTimestamp()
+	}
+	entry := &LogEntry{
+		Timestamp: jvm.FormatTimestamp(timestamp.AsTime(), c.timeFormat),
+		Level:     logLevel(logRecord),
+		// Java 日志桥接以 logger 名作为 scope 名
+		Logger:  scopeName,
+		Thread:  stringAttribute(attributes, jvm.THREAD_NAME),
+		Message: logRecord.Body().AsString(),
+	}
+	if entry.Logger == "" {
+		entry.Logger = stringAttribute(attributes, CODE_NAMESPACE)
+	}
+	if !logRecord.TraceID().IsEmpty() {
+		entry.TraceId = logRecord.TraceID().String()
+	}
+	if !logRecord.SpanID().IsEmpty() {
+		entry.SpanId = logRecord.SpanID().String()
+	}
+	exceptionType := stringAttribute(attributes, EXCEPTION_TYPE)
+	stackTrace := stringAttribute(attributes, EXCEPTION_STACKTRACE)
+	if exceptionType != "" || stackTrace != "" {
+		entry.Exception = &LogException{
+			Type:       exceptionType,
+			Message:    stringAttribute(attributes, EXCEPTION_MESSAGE),
+			StackTrace: stackTrace,
+		}
+	}
+	return entry
+}
+
+// logLevel 根据 SeverityNumber 映射日志级别，没有时使用 SeverityText，都没有时为 INFO
+func logLevel(logRecord plog.LogRecord) string {
+	switch severity := logRecord.SeverityNumber(); {
+	case severity >= plog.SeverityNumberFatal:
+		return logLevelFatal
+	case severity >= plog.SeverityNumberError:
+		return logLevelError
+	case severity >= plog.SeverityNumberWarn:
+		return logLevelWarn
+	case severity >= plog.SeverityNumberInfo:
+		return logLevelInfo
+	case severity >= plog.SeverityNumberDebug:
+		return logLevelDebug
+	case severity >= plog.SeverityNumberTrace:
+		return logLevelTrace
+	}
+	// java.util.logging 等框架的级别名
+	switch text := strings.ToUpper(logRecord.SeverityText()); text {
+	case "":
+		return logLevelInfo
+	case "FINEST", "FINER":
+		return logLevelTrace
+	case "FINE", "CONFIG":
+		return logLevelDebug
+	case "WARNING":
+		return logLevelWarn
+	case "SEVERE":
+		return logLevelError
+	case "CRITICAL", "PANIC":
+		return logLevelFatal
+	default:
+		return text
+	}
+}
@@ -0,0 +1,169 @@
+package jvmhttpexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestLogConverter(t *testing.T, modify func(*Config)) *logConverter {
+	cfg := createDefaultConfig().(*Config)
+	if modify != nil {
+		modify(cfg)
+	}
+	converter, err := newLogConverter(cfg)
+	require.NoError(t, err)
+	return converter
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity plog.SeverityNumber
+		text     string
+		want     string
+	}{
+		{name: "trace", severity: plog.SeverityNumberTrace2, want: "TRACE"},
+		{name: "debug", severity: plog.SeverityNumberDebug, want: "DEBUG"},
+		{name: "info", severity: plog.SeverityNumberInfo4, want: "INFO"},
+		{name: "warn", severity: plog.SeverityNumberWarn, want: "WARN"},
+		{name: "error", severity: plog.SeverityNumberError3, want: "ERROR"},
+		{name: "fatal", severity: plog.SeverityNumberFatal, want: "FATAL"},
+		{name: "number takes precedence over text", severity: plog.SeverityNumberError, text: "INFO", want: "ERROR"},
+		{name: "no severity", want: "INFO"},
+		{name: "text", text: "warn", want: "WARN"},
+		{name: "jul finest", text: "FINEST", want: "TRACE"},
+		{name: "jul fine", text: "FINE", want: "DEBUG"},
+		{name: "jul config", text: "CONFIG", want: "DEBUG"},
+		{name: "jul warning", text: "WARNING", want: "WARN"},
+		{name: "jul severe", text: "SEVERE", want: "ERROR"},
+		{name: "critical", text: "critical", want: "FATAL"},
+		{name: "unknown text", text: "notice", want: "NOTICE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logRecord := plog.NewLogRecord()
+			logRecord.SetSeverityNumber(tt.severity)
+			logRecord.SetSeverityText(tt.text)
+			assert.Equal(t, tt.want, logLevel(logRecord))
+		})
+	}
+}
+
+func TestLogTransform(t *testing.T) {
+	timestamp := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	ld := plog.NewLogs()
+	resourceLog := ld.ResourceLogs().AppendEmpty()
+	resourceLog.Resource().Attributes().PutStr("service.name", "bookdemo")
+	resourceLog.Resource().Attributes().PutStr("service.instance.id", "0b5c")
+	resourceLog.Resource().Attributes().PutStr("host.ip", "192.168.136.105")
+	scopeLog := resourceLog.ScopeLogs().AppendEmpty()
+	scopeLog.Scope().SetName("com.example.BookController")
+
+	info := scopeLog.LogRecords().AppendEmpty()
+	info.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	info.SetSeverityNumber(plog.SeverityNumberInfo)
+	info.Body().SetStr("book 7 found")
+	info.Attributes().PutStr(jvm.THREAD_NAME, "http-nio-8080-exec-1")
+	info.SetTraceID(pcommon.TraceID{1})
+	info.SetSpanID(pcommon.SpanID{2})
+
+	failure := scopeLog.LogRecords().AppendEmpty()
+	failure.SetObservedTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	failure.SetSeverityText("SEVERE")
+	failure.Body().SetStr("failed to load book 8")
+	failure.Attributes().PutStr(EXCEPTION_TYPE, "java.sql.SQLException")
+	failure.Attributes().PutStr(EXCEPTION_MESSAGE, "Connection is not available")
+	failure.Attributes().PutStr(EXCEPTION_STACKTRACE, "java.sql.SQLException: Connection is not available\n\tat com.zaxxer.hikari.pool.HikariPool.getConnection")
+
+	// 线程详情事件只用于指标
+	dump := scopeLog.LogRecords().AppendEmpty()
+	dump.SetEventName(jvm.JVM_THREAD_DUMP_EVENT)
+
+	// 没有 scope 名时使用 code.namespace 作为 logger
+	bridged := resourceLog.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	bridged.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	bridged.Body().SetStr("cache warmed")
+	bridged.Attributes().PutStr(CODE_NAMESPACE, "com.example.Cache")
+
+	requests, err := newTestLogConverter(t, nil).logTransform(ld)
+	require.NoError(t, err)
+	datas := decodeTestData(t, requests)
+	require.Len(t, datas, 2)
+
+	agentID := "bookdemo-unknown-0b5c@192.168.136.105:0"
+	assert.Equal(t, defaultLogsLogType, datas[0].LogType)
+	assert.Equal(t, "192.168.136.105", datas[0].MasterIp)
+	assert.Equal(t, []*LogEntry{
+		{
+			AgentId: agentID, MultiAgentId: agentID, AppName: "bookdemo",
+			Timestamp: "1741593600000", Level: "INFO", Logger: "com.example.BookController",
+			Thread: "http-nio-8080-exec-1", Message: "book 7 found",
+			TraceId: "01000000000000000000000000000000", SpanId: "0200000000000000",
+		},
+		{
+			AgentId: agentID, MultiAgentId: agentID, AppName: "bookdemo",
+			Timestamp: "1741593600000", Level: "INFO", Logger: "com.example.Cache", Message: "cache warmed",
+		},
+	}, datas[0].LogMessage.Logs)
+
+	assert.Equal(t, defaultExceptionsLogType, datas[1].LogType)
+	assert.Equal(t, []*LogEntry{{
+		AgentId: agentID, MultiAgentId: agentID, AppName: "bookdemo",
+		Timestamp: "1741593600000", Level: "ERROR", Logger: "com.example.BookController", Message: "failed to load book 8",
+		Exception: &LogException{
+			Type:       "java.sql.SQLException",
+			Message:    "Connection is not available",
+			StackTrace: "java.sql.SQLException: Connection is not available\n\tat com.zaxxer.hikari.pool.HikariPool.getConnection",
+		},
+	}}, datas[1].LogMessage.Logs)
+}
+
+func TestLogTransformLogTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		// 每个请求体的 logType 和日志条数
+		want map[string]int
+	}{
+		{
+			name: "exceptions are routed to their own logType",
+			want: map[string]int{defaultLogsLogType: 2, defaultExceptionsLogType: 1},
+		},
+		{
+			name:   "exceptions without their own logType",
+			modify: func(cfg *Config) { cfg.LogTypes.Exceptions = "" },
+			want:   map[string]int{defaultLogsLogType: 3},
+		},
+		{
+			name: "custom logTypes",
+			modify: func(cfg *Config) {
+				cfg.LogTypes.Logs = "AppLog"
+				cfg.LogTypes.Exceptions = "AppException"
+			},
+			want: map[string]int{"AppLog": 2, "AppException": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ld := plog.NewLogs()
+			logRecords := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			logRecords.AppendEmpty().Body().SetStr("started")
+			logRecords.AppendEmpty().Attributes().PutStr(EXCEPTION_STACKTRACE, "java.lang.IllegalStateException")
+			logRecords.AppendEmpty().Body().SetStr("stopped")
+
+			requests, err := newTestLogConverter(t, tt.modify).logTransform(ld)
+			require.NoError(t, err)
+			got := make(map[string]int)
+			for _, data := range decodeTestData(t, requests) {
+				got[data.LogType] += len(data.LogMessage.Logs)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package jvmhttpexporter
+
+import (
+	"errors"
+)
+
+const (
+	defaultMetricsLogType    = "JavaManagementData"
+	defaultTracesLogType     = "JavaTransactionData"
+	defaultLogsLogType       = "JavaLogData"
+	defaultExceptionsLogType = "JavaExceptionData"
+)
+
+// LogTypesConfig defines the logType reported in the envelope of each signal.
+type LogTypesConfig struct {
+	// Metrics is the logType of JVM management payloads.
+	Metrics string `mapstructure:"metrics"`
+
+	// Traces is the logType of transaction payloads converted from spans.
+	Traces string `mapstructure:"traces"`
+
+	// Logs is the logType of log records.
+	Logs string `mapstructure:"logs"`
+
+	// Exceptions is the logType of log records carrying exception.type or exception.stacktrace.
+	// If empty they are reported with the Logs logType.
+	Exceptions string `mapstructure:"exceptions"`
+}
+
+func newDefaultLogTypesConfig() LogTypesConfig {
+	return LogTypesConfig{
+		Metrics:    defaultMetricsLogType,
+		Traces:     defaultTracesLogType,
+		Logs:       defaultLogsLogType,
+		Exceptions: defaultExceptionsLogType,
+	}
+}
+
+// Validate checks that every signal has a logType.
+func (cfg *LogTypesConfig) Validate() error {
+	if cfg.Metrics == "" {
+		return errors.New("metrics must not be empty")
+	}
+	if cfg.Traces == "" {
+		return errors.New("traces must not be empty")
+	}
+	if cfg.Logs == "" {
+		return errors.New("logs must not be empty")
+	}
+	return nil
+}
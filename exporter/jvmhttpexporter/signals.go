@@ -0,0 +1,78 @@
+package jvmhttpexporter
+
+import (
+	"errors"
+	"maps"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+// SignalConfig overrides the transport settings of one signal. Fields that are not set use the
+// top level settings. Each signal builds its own HTTP client from the merged settings.
+type SignalConfig struct {
+	// Endpoint is the URL the signal is sent to. It takes precedence over Endpoint and must not be
+	// combined with the <signal>_endpoint setting.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Headers are added to the top level headers, replacing top level headers with the same name.
+	Headers map[string]configopaque.String `mapstructure:"headers"`
+
+	// Encoding overrides encoding.
+	Encoding EncodingType `mapstructure:"encoding"`
+
+	// Compression overrides compression. Use "none" to disable compression for the signal.
+	Compression configcompression.Type `mapstructure:"compression"`
+
+	// Timeout overrides timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Validate checks the signal overrides.
+func (cfg *SignalConfig) Validate() error {
+	if cfg.Endpoint != "" {
+		if _, err := url.Parse(cfg.Endpoint); err != nil {
+			return errors.New("endpoint must be a valid URL")
+		}
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	return nil
+}
+
+// endpoint 返回信号的地址覆盖，没有时使用旧的 <signal>_endpoint 配置
+func (cfg *SignalConfig) endpoint(legacy string) string {
+	if cfg.Endpoint != "" {
+		return cfg.Endpoint
+	}
+	return legacy
+}
+
+// encoding 返回信号使用的编码
+func (cfg *SignalConfig) encoding(base EncodingType) EncodingType {
+	if cfg.Encoding != "" {
+		return cfg.Encoding
+	}
+	return base
+}
+
+// clientConfig 返回应用了信号覆盖配置的 HTTP 客户端配置，不修改 base
+func (cfg *SignalConfig) clientConfig(base confighttp.ClientConfig) confighttp.ClientConfig {
+	if len(cfg.Headers) > 0 {
+		headers := make(map[string]configopaque.String, len(base.Headers)+len(cfg.Headers))
+		maps.Copy(headers, base.Headers)
+		maps.Copy(headers, cfg.Headers)
+		base.Headers = headers
+	}
+	if cfg.Compression != "" {
+		base.Compression = cfg.Compression
+	}
+	if cfg.Timeout > 0 {
+		base.Timeout = cfg.Timeout
+	}
+	return base
+}
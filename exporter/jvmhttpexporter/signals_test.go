@@ -0,0 +1,214 @@
+package jvmhttpexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
+func TestSignalConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SignalConfig
+		wantErr string
+	}{
+		{name: "empty", cfg: SignalConfig{}},
+		{name: "overrides", cfg: SignalConfig{Endpoint: "http://localhost:4318/v1/traces", Encoding: EncodingProto, Timeout: time.Second}},
+		{name: "invalid endpoint", cfg: SignalConfig{Endpoint: "http://local host:%zz"}, wantErr: "endpoint must be a valid URL"},
+		{name: "negative timeout", cfg: SignalConfig{Timeout: -time.Second}, wantErr: "timeout must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSignalConfigOverrides(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          SignalConfig
+		legacy       string
+		wantEndpoint string
+		wantEncoding EncodingType
+	}{
+		{name: "top level settings", wantEncoding: EncodingJSON},
+		{name: "legacy endpoint", legacy: "http://legacy/v1/traces", wantEndpoint: "http://legacy/v1/traces", wantEncoding: EncodingJSON},
+		{
+			name:         "signal overrides",
+			cfg:          SignalConfig{Endpoint: "http://signal/v1/traces", Encoding: EncodingProto},
+			legacy:       "http://legacy/v1/traces",
+			wantEndpoint: "http://signal/v1/traces",
+			wantEncoding: EncodingProto,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantEndpoint, tt.cfg.endpoint(tt.legacy))
+			assert.Equal(t, tt.wantEncoding, tt.cfg.encoding(EncodingJSON))
+		})
+	}
+}
+
+func TestSignalConfigClientConfig(t *testing.T) {
+	base := confighttp.NewDefaultClientConfig()
+	base.Endpoint = "http://localhost:4318"
+	base.Timeout = 10 * time.Second
+	base.Compression = configcompression.TypeGzip
+	base.Headers = map[string]configopaque.String{"Authorization": "Bearer base", "X-Tenant": "a"}
+
+	tests := []struct {
+		name            string
+		cfg             SignalConfig
+		wantHeaders     map[string]configopaque.String
+		wantCompression configcompression.Type
+		wantTimeout     time.Duration
+	}{
+		{
+			name:            "no overrides",
+			wantHeaders:     map[string]configopaque.String{"Authorization": "Bearer base", "X-Tenant": "a"},
+			wantCompression: configcompression.TypeGzip,
+			wantTimeout:     10 * time.Second,
+		},
+		{
+			name: "headers are merged",
+			cfg: SignalConfig{Headers: map[string]configopaque.String{
+				"X-Tenant": "b",
+				"X-Signal": "logs",
+			}},
+			wantHeaders:     map[string]configopaque.String{"Authorization": "Bearer base", "X-Tenant": "b", "X-Signal": "logs"},
+			wantCompression: configcompression.TypeGzip,
+			wantTimeout:     10 * time.Second,
+		},
+		{
+			name:            "compression and timeout",
+			cfg:             SignalConfig{Compression: "none", Timeout: time.Minute},
+			wantHeaders:     map[string]configopaque.String{"Authorization": "Bearer base", "X-Tenant": "a"},
+			wantCompression: "none",
+			wantTimeout:     time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cfg.clientConfig(base)
+			assert.Equal(t, tt.wantHeaders, got.Headers)
+			assert.Equal(t, tt.wantCompression, got.Compression)
+			assert.Equal(t, tt.wantTimeout, got.Timeout)
+			assert.Equal(t, base.Endpoint, got.Endpoint)
+			// 覆盖配置不能修改顶层配置
+			assert.Equal(t, map[string]configopaque.String{"Authorization": "Bearer base", "X-Tenant": "a"}, base.Headers)
+		})
+	}
+}
+
+func TestConfigValidateSignals(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:   "signal endpoint only",
+			modify: func(cfg *Config) { cfg.Traces.Endpoint = "http://localhost:4318/v1/traces" },
+		},
+		{
+			name: "signal endpoint and legacy endpoint",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Logs.Endpoint = "http://localhost:4318/v1/logs"
+				cfg.LogsEndpoint = "http://localhost:4318/v1/logs"
+			},
+			wantErr: "logs.endpoint and logs_endpoint must not both be specified",
+		},
+		{
+			name: "invalid signal override",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Metrics.Timeout = -time.Second
+			},
+			wantErr: "metrics: timeout must not be negative",
+		},
+		{
+			name: "proto for forwarded traces",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Traces.Encoding = EncodingProto
+			},
+		},
+		{
+			name: "proto for converted logs",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Conversion.Logs = true
+				cfg.Logs.Encoding = EncodingProto
+			},
+			wantErr: "conversion.logs requires the json encoding",
+		},
+		{
+			name: "json for converted logs overrides proto",
+			modify: func(cfg *Config) {
+				cfg.Endpoint = "http://localhost:4318"
+				cfg.Conversion.Logs = true
+				cfg.Encoding = EncodingProto
+				cfg.Logs.Encoding = EncodingJSON
+			},
+		},
+		{
+			name:    "no endpoint",
+			modify:  func(*Config) {},
+			wantErr: "at least one endpoint must be specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestComposeSignalURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		failover  []string
+		override  string
+		want      string
+		wantError string
+	}{
+		{name: "endpoint", endpoint: "http://localhost:4318", want: "http://localhost:4318/v1/traces"},
+		{name: "endpoint with trailing slash", endpoint: "http://localhost:4318/", want: "http://localhost:4318/v1/traces"},
+		{name: "override", endpoint: "http://localhost:4318", override: "http://traces:4318/custom", want: "http://traces:4318/custom"},
+		{name: "first failover endpoint", failover: []string{"http://a:4318", "http://b:4318"}, want: "http://a:4318/v1/traces"},
+		{name: "no endpoint", wantError: "either endpoint or traces_endpoint must be specified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = tt.endpoint
+			cfg.Failover.Endpoints = tt.failover
+			got, err := composeSignalURL(cfg, tt.override, "traces", "v1")
+			if tt.wantError != "" {
+				assert.EqualError(t, err, tt.wantError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package jvmhttpexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// endpointTelemetry holds the instruments the exporter uses to report on its failover endpoints.
+type endpointTelemetry struct {
+	endpointCalls metric.Int64Counter
+	endpointsDown metric.Int64UpDownCounter
+}
+
+func newEndpointTelemetry(set component.TelemetrySettings) (*endpointTelemetry, error) {
+	meterProvider := set.MeterProvider
+	if meterProvider == nil {
+		meterProvider = noop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(ScopeName)
+
+	endpointCalls, err := meter.Int64Counter(
+		"exporter_jvm_endpoint_requests",
+		metric.WithDescription("Number of requests sent to each failover endpoint, by outcome."),
+		metric.WithUnit("{requests}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	endpointsDown, err := meter.Int64UpDownCounter(
+		"exporter_jvm_endpoints_down",
+		metric.WithDescription("Number of failover endpoints currently marked down."),
+		metric.WithUnit("{endpoints}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &endpointTelemetry{
+		endpointCalls: endpointCalls,
+		endpointsDown: endpointsDown,
+	}, nil
+}
+
+// 地址以其在 failover.endpoints 中的位置标识，URL 只记录在日志中
+func (t *endpointTelemetry) recordEndpointRequest(signal string, endpoint int, outcome string) {
+	t.endpointCalls.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("signal", signal),
+		attribute.Int("endpoint", endpoint),
+		attribute.String("outcome", outcome),
+	))
+}
+
+func (t *endpointTelemetry) recordEndpointDown(signal string, delta int64) {
+	t.endpointsDown.Add(context.Background(), delta, metric.WithAttributes(
+		attribute.String("signal", signal),
+	))
+}
@@ -0,0 +1,379 @@
+package jvmhttpexporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+const (
+	// HTTP 属性，同时兼容新旧两版语义约定
+	HTTP_REQUEST_METHOD       = "http.request.method"
+	HTTP_METHOD               = "http.method"
+	HTTP_RESPONSE_STATUS_CODE = "http.response.status_code"
+	HTTP_STATUS_CODE          = "http.status_code"
+	HTTP_ROUTE                = "http.route"
+	HTTP_TARGET               = "http.target"
+	HTTP_URL                  = "http.url"
+	URL_FULL                  = "url.full"
+	URL_PATH                  = "url.path"
+	URL_QUERY                 = "url.query"
+
+	// 数据库属性
+	DB_SYSTEM      = "db.system"
+	DB_SYSTEM_NAME = "db.system.name"
+	DB_STATEMENT   = "db.statement"
+	DB_QUERY_TEXT  = "db.query.text"
+	DB_NAME        = "db.name"
+	DB_NAMESPACE   = "db.namespace"
+
+	RPC_SYSTEM       = "rpc.system"
+	MESSAGING_SYSTEM = "messaging.system"
+
+	SERVER_ADDRESS = "server.address"
+	SERVER_PORT    = "server.port"
+	NET_PEER_NAME  = "net.peer.name"
+	NET_PEER_PORT  = "net.peer.port"
+
+	EXCEPTION_EVENT   = "exception"
+	EXCEPTION_MESSAGE = "exception.message"
+
+	callTypeHTTP      = "http"
+	callTypeDB        = "db"
+	callTypeRPC       = "rpc"
+	callTypeMessaging = "messaging"
+)
+
+// Transaction 对应一个服务端或消费者 span，Calls 为其下游调用
+type Transaction struct {
+	AgentId      string `json:"agentId"`
+	MultiAgentId string `json:"multiAgentId"`
+	AppName      string `json:"appName"`
+	TraceId      string `json:"traceId"`
+	SpanId       string `json:"spanId"`
+	ParentSpanId string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	StartTime    string `json:"startTime"`
+	// Duration 单位为毫秒
+	Duration     float64 `json:"duration"`
+	Method       string  `json:"method"`
+	Url          string  `json:"url"`
+	StatusCode   int64   `json:"statusCode"`
+	Error        bool    `json:"error"`
+	ErrorMessage string  `json:"errorMessage"`
+	// Partial 为 true 表示入口 span 不在同一批次中，SpanId 为调用链上缺失的父 span
+	Partial bool   `json:"partial"`
+	Calls   []Call `json:"calls"`
+}
+
+// Call 对应一个客户端、生产者或数据库 span
+type Call struct {
+	Type         string `json:"type"`
+	SpanId       string `json:"spanId"`
+	ParentSpanId string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	StartTime    string `json:"startTime"`
+	// Duration 单位为毫秒
+	Duration     float64 `json:"duration"`
+	Peer         string  `json:"peer"`
+	Method       string  `json:"method"`
+	Url          string  `json:"url"`
+	StatusCode   int64   `json:"statusCode"`
+	DbSystem     string  `json:"dbSystem"`
+	DbName       string  `json:"dbName"`
+	Sql          string  `json:"sql"`
+	Error        bool    `json:"error"`
+	ErrorMessage string  `json:"errorMessage"`
+}
+
+// traceConverter 负责将 OTLP span 转换为内部事务格式
+type traceConverter struct {
+	identity   *identity
+	timeFormat string
+	logType    string
+	logger     *zap.Logger
+}
+
+func newTraceConverter(cfg *Config, set component.TelemetrySettings) (*traceConverter, error) {
+	id, err := newIdentity(cfg.Identity)
+	if err != nil {
+		return nil, err
+	}
+	return &traceConverter{
+		identity:   id,
+		timeFormat: cfg.TimestampFormat,
+		logType:    cfg.LogTypes.Traces,
+		logger:     set.Logger,
+	}, nil
+}
+
+// traceTransform 将 OTLP span 按 JVM 实例转换为内部事务格式，每个 JVM 一个请求体；
+// 批次中没有事务时返回空
+func (c *traceConverter) traceTransform(td ptrace.Traces) ([][]byte, error) {
+	type jvmTransactions struct {
+		data  *Data
+		index map[string]*Transaction
+	}
+	batches := make(map[string]*jvmTransactions)
+	var keys []string
+
+	resourceSpans := td.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		resourceSpan := resourceSpans.At(i)
+		resourceAttributes := resourceSpan.Resource().Attributes()
+		key := jvm.ResourceKey(resourceAttributes)
+		batch, ok := batches[key]
+		if !ok {
+			batch = &jvmTransactions{
+				data: &Data{
+					LogMessage: &LogMessage{ApmLang: c.identity.apmLang},
+					LogType:    c.logType,
+					MasterIp:   c.identity.masterIP.Render(resourceAttributes),
+				},
+				index: make(map[string]*Transaction),
+			}
+			batches[key] = batch
+			keys = append(keys, key)
+		}
+
+		agentID, multiAgentID := c.identity.AgentIDs(resourceAttributes, key)
+		var appName string
+		if v, ok := resourceAttributes.Get(jvm.SERVICE_NAME); ok {
+			appName = v.AsString()
+		}
+
+		// spans 按批次中的顺序保存，byID 用于沿父 span 查找
+		var spans []ptrace.Span
+		byID := make(map[pcommon.SpanID]ptrace.Span)
+		scopeSpans := resourceSpan.ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			ss := scopeSpans.At(j).Spans()
+			for k := 0; k < ss.Len(); k++ {
+				spans = append(spans, ss.At(k))
+				byID[ss.At(k).SpanID()] = ss.At(k)
+			}
+		}
+		// 先生成事务，再把调用挂到调用链上最近的事务下
+		for _, span := range spans {
+			if isTransactionSpan(span) {
+				transaction := c.newTransaction(span)
+				transaction.AgentId, transaction.MultiAgentId, transaction.AppName = agentID, multiAgentID, appName
+				batch.index[transaction.SpanId] = transaction
+				batch.data.LogMessage.Transactions = append(batch.data.LogMessage.Transactions, transaction)
+			}
+		}
+		dropped := 0
+		for _, span := range spans {
+			callType, ok := spanCallType(span)
+			if !ok || isTransactionSpan(span) {
+				continue
+			}
+			owner := transactionSpanID(span, byID)
+			if owner.IsEmpty() {
+				dropped++
+				continue
+			}
+			transaction, ok := batch.index[owner.String()]
+			if !ok {
+				// 入口 span 不在本批次中，以缺失的父 span 作为事务标识
+				transaction = &Transaction{
+					AgentId:      agentID,
+					MultiAgentId: multiAgentID,
+					AppName:      appName,
+					TraceId:      span.TraceID().String(),
+					SpanId:       owner.String(),
+					Partial:      true,
+				}
+				batch.index[transaction.SpanId] = transaction
+				batch.data.LogMessage.Transactions = append(batch.data.LogMessage.Transactions, transaction)
+			}
+			transaction.Calls = append(transaction.Calls, c.newCall(callType, span))
+		}
+		if dropped > 0 {
+			c.logger.Debug("Dropped calls without a parent span", zap.Int("calls", dropped))
+		}
+	}
+
+	requests := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		batch := batches[key]
+		if len(batch.data.LogMessage.Transactions) == 0 {
+			continue
+		}
+		jsonBytes, err := json.Marshal(batch.data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal transactions of %q: %w", key, err)
+		}
+		requests = append(requests, jsonBytes)
+	}
+	return requests, nil
+}
+
+// isTransactionSpan 判断 span 是否为事务：服务端、消费者 span 以及没有父 span 的入口 span
+func isTransactionSpan(span ptrace.Span) bool {
+	switch span.Kind() {
+	case ptrace.SpanKindServer, ptrace.SpanKindConsumer:
+		return true
+	case ptrace.SpanKindClient, ptrace.SpanKindProducer:
+		return false
+	}
+	return span.ParentSpanID().IsEmpty()
+}
+
+// spanCallType 判断 span 是否为下游调用及其类型，带 db.system 的内部 span 也视为数据库调用
+func spanCallType(span ptrace.Span) (string, bool) {
+	attributes := span.Attributes()
+	if _, ok := firstAttribute(attributes, DB_SYSTEM_NAME, DB_SYSTEM); ok {
+		return callTypeDB, true
+	}
+	if span.Kind() != ptrace.SpanKindClient && span.Kind() != ptrace.SpanKindProducer {
+		return "", false
+	}
+	switch {
+	case hasAttribute(attributes, MESSAGING_SYSTEM) || span.Kind() == ptrace.SpanKindProducer:
+		return callTypeMessaging, true
+	case hasAttribute(attributes, RPC_SYSTEM):
+		return callTypeRPC, true
+	}
+	return callTypeHTTP, true
+}
+
+// transactionSpanID 沿父 span 向上查找所属事务，父 span 不在批次中时返回缺失的父 span ID
+func transactionSpanID(span ptrace.Span, spans map[pcommon.SpanID]ptrace.Span) pcommon.SpanID {
+	visited := make(map[pcommon.SpanID]bool)
+	for {
+		parentID := span.ParentSpanID()
+		if parentID.IsEmpty() || visited[parentID] {
+			return pcommon.NewSpanIDEmpty()
+		}
+		visited[parentID] = true
+		parent, ok := spans[parentID]
+		if !ok || isTransactionSpan(parent) {
+			return parentID
+		}
+		span = parent
+	}
+}
+
+func (c *traceConverter) newTransaction(span ptrace.Span) *Transaction {
+	attributes := span.Attributes()
+	transaction := &Transaction{
+		TraceId:      span.TraceID().String(),
+		SpanId:       span.SpanID().String(),
+		ParentSpanId: span.ParentSpanID().String(),
+		Name:         span.Name(),
+		Kind:         span.Kind().String(),
+		StartTime:    jvm.FormatTimestamp(span.StartTimestamp().AsTime(), c.timeFormat),
+		Duration:     spanDuration(span),
+		Method:       stringAttribute(attributes, HTTP_REQUEST_METHOD, HTTP_METHOD),
+		Url:          requestPath(attributes),
+		StatusCode:   int64Attribute(attributes, HTTP_RESPONSE_STATUS_CODE, HTTP_STATUS_CODE),
+	}
+	transaction.Error, transaction.ErrorMessage = spanError(span)
+	return transaction
+}
+
+func (c *traceConverter) newCall(callType string, span ptrace.Span) Call {
+	attributes := span.Attributes()
+	call := Call{
+		Type:         callType,
+		SpanId:       span.SpanID().String(),
+		ParentSpanId: span.ParentSpanID().String(),
+		Name:         span.Name(),
+		StartTime:    jvm.FormatTimestamp(span.StartTimestamp().AsTime(), c.timeFormat),
+		Duration:     spanDuration(span),
+		Peer:         peerAddress(attributes),
+		Method:       stringAttribute(attributes, HTTP_REQUEST_METHOD, HTTP_METHOD),
+		Url:          stringAttribute(attributes, URL_FULL, HTTP_URL),
+		StatusCode:   int64Attribute(attributes, HTTP_RESPONSE_STATUS_CODE, HTTP_STATUS_CODE),
+		DbSystem:     stringAttribute(attributes, DB_SYSTEM_NAME, DB_SYSTEM),
+		DbName:       stringAttribute(attributes, DB_NAMESPACE, DB_NAME),
+		Sql:          stringAttribute(attributes, DB_QUERY_TEXT, DB_STATEMENT),
+	}
+	call.Error, call.ErrorMessage = spanError(span)
+	return call
+}
+
+// spanDuration 返回 span 耗时，单位为毫秒
+func spanDuration(span ptrace.Span) float64 {
+	if span.EndTimestamp() < span.StartTimestamp() {
+		return 0
+	}
+	return float64(span.EndTimestamp()-span.StartTimestamp()) / 1e6
+}
+
+// spanError 返回 span 是否出错及错误信息，优先使用 span 状态中的描述，其次是异常事件
+func spanError(span ptrace.Span) (bool, string) {
+	if span.Status().Code() != ptrace.StatusCodeError {
+		return false, ""
+	}
+	if message := span.Status().Message(); message != "" {
+		return true, message
+	}
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		if events.At(i).Name() == EXCEPTION_EVENT {
+			return true, stringAttribute(events.At(i).Attributes(), EXCEPTION_MESSAGE)
+		}
+	}
+	return true, ""
+}
+
+// requestPath 返回服务端 span 的请求路径，依次尝试 url.path?url.query、http.target 和 http.route
+func requestPath(attributes pcommon.Map) string {
+	if path := stringAttribute(attributes, URL_PATH); path != "" {
+		if query := stringAttribute(attributes, URL_QUERY); query != "" {
+			return path + "?" + query
+		}
+		return path
+	}
+	return stringAttribute(attributes, HTTP_TARGET, HTTP_ROUTE, URL_FULL, HTTP_URL)
+}
+
+// peerAddress 返回下游地址 host:port
+func peerAddress(attributes pcommon.Map) string {
+	host := stringAttribute(attributes, SERVER_ADDRESS, NET_PEER_NAME)
+	if host == "" {
+		return ""
+	}
+	if port := int64Attribute(attributes, SERVER_PORT, NET_PEER_PORT); port > 0 {
+		return host + ":" + strconv.FormatInt(port, 10)
+	}
+	return host
+}
+
+// firstAttribute 返回第一个存在的属性
+func firstAttribute(attributes pcommon.Map, names ...string) (pcommon.Value, bool) {
+	for _, name := range names {
+		if v, ok := attributes.Get(name); ok {
+			return v, true
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+func hasAttribute(attributes pcommon.Map, name string) bool {
+	_, ok := attributes.Get(name)
+	return ok
+}
+
+func stringAttribute(attributes pcommon.Map, names ...string) string {
+	if v, ok := firstAttribute(attributes, names...); ok {
+		return v.AsString()
+	}
+	return ""
+}
+
+func int64Attribute(attributes pcommon.Map, names ...string) int64 {
+	if v, ok := firstAttribute(attributes, names...); ok {
+		return jvm.IntAttribute(v)
+	}
+	return 0
+}
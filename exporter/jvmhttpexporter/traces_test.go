@@ -0,0 +1,227 @@
+package jvmhttpexporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+var testSpanStart = time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+// appendTestSpan 添加一个耗时 10ms 的 span，id 和 parent 为 span ID 的最后一个字节，parent 为 0 表示没有父 span
+func appendTestSpan(spans ptrace.SpanSlice, kind ptrace.SpanKind, id, parent byte, name string, attributes map[string]any) ptrace.Span {
+	span := spans.AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{1})
+	span.SetSpanID(pcommon.SpanID{0, 0, 0, 0, 0, 0, 0, id})
+	if parent != 0 {
+		span.SetParentSpanID(pcommon.SpanID{0, 0, 0, 0, 0, 0, 0, parent})
+	}
+	span.SetKind(kind)
+	span.SetName(name)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(testSpanStart))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(testSpanStart.Add(10 * time.Millisecond)))
+	_ = span.Attributes().FromRaw(attributes)
+	return span
+}
+
+func newTestTraces(attributes map[string]any) (ptrace.Traces, ptrace.SpanSlice) {
+	td := ptrace.NewTraces()
+	resourceSpan := td.ResourceSpans().AppendEmpty()
+	_ = resourceSpan.Resource().Attributes().FromRaw(attributes)
+	return td, resourceSpan.ScopeSpans().AppendEmpty().Spans()
+}
+
+func decodeTestData(t *testing.T, requests [][]byte) []Data {
+	datas := make([]Data, 0, len(requests))
+	for _, request := range requests {
+		var data Data
+		require.NoError(t, json.Unmarshal(request, &data))
+		datas = append(datas, data)
+	}
+	return datas
+}
+
+func newTestTraceConverter(t *testing.T) *traceConverter {
+	converter, err := newTraceConverter(createDefaultConfig().(*Config), component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	return converter
+}
+
+func TestTraceTransform(t *testing.T) {
+	td, spans := newTestTraces(map[string]any{"service.name": "bookdemo", "service.instance.id": "0b5c", "host.ip": "192.168.136.105"})
+	server := appendTestSpan(spans, ptrace.SpanKindServer, 1, 0, "GET /books/{id}", map[string]any{
+		HTTP_REQUEST_METHOD:       "GET",
+		URL_PATH:                  "/books/7",
+		URL_QUERY:                 "format=json",
+		HTTP_ROUTE:                "/books/{id}",
+		HTTP_RESPONSE_STATUS_CODE: int64(500),
+	})
+	server.Status().SetCode(ptrace.StatusCodeError)
+	server.Status().SetMessage("book store unavailable")
+	appendTestSpan(spans, ptrace.SpanKindInternal, 2, 1, "BookService.find", nil)
+	appendTestSpan(spans, ptrace.SpanKindClient, 3, 2, "SELECT books", map[string]any{
+		DB_SYSTEM:      "mysql",
+		DB_NAME:        "library",
+		DB_STATEMENT:   "SELECT * FROM books WHERE id = ?",
+		NET_PEER_NAME:  "mysql",
+		NET_PEER_PORT:  int64(3306),
+		SERVER_ADDRESS: "mysql.default",
+	})
+	client := appendTestSpan(spans, ptrace.SpanKindClient, 4, 1, "GET", map[string]any{
+		HTTP_METHOD:      "GET",
+		URL_FULL:         "http://stock:8080/stock/7",
+		HTTP_STATUS_CODE: "503",
+		SERVER_ADDRESS:   "stock",
+		SERVER_PORT:      int64(8080),
+	})
+	client.Status().SetCode(ptrace.StatusCodeError)
+	exception := client.Events().AppendEmpty()
+	exception.SetName(EXCEPTION_EVENT)
+	exception.Attributes().PutStr(EXCEPTION_MESSAGE, "connection refused")
+	appendTestSpan(spans, ptrace.SpanKindProducer, 5, 1, "orders publish", map[string]any{MESSAGING_SYSTEM: "kafka"})
+	appendTestSpan(spans, ptrace.SpanKindClient, 6, 1, "StockService/Reserve", map[string]any{RPC_SYSTEM: "grpc"})
+
+	requests, err := newTestTraceConverter(t).traceTransform(td)
+	require.NoError(t, err)
+	datas := decodeTestData(t, requests)
+	require.Len(t, datas, 1)
+	assert.Equal(t, defaultTracesLogType, datas[0].LogType)
+	assert.Equal(t, "192.168.136.105", datas[0].MasterIp)
+	require.Len(t, datas[0].LogMessage.Transactions, 1)
+
+	transaction := datas[0].LogMessage.Transactions[0]
+	startTime := "1741593600000"
+	assert.Equal(t, &Transaction{
+		AgentId:      "bookdemo-unknown-0b5c@192.168.136.105:0",
+		MultiAgentId: "bookdemo-unknown-0b5c@192.168.136.105:0",
+		AppName:      "bookdemo",
+		TraceId:      "01000000000000000000000000000000",
+		SpanId:       "0000000000000001",
+		Name:         "GET /books/{id}",
+		Kind:         "Server",
+		StartTime:    startTime,
+		Duration:     10,
+		Method:       "GET",
+		Url:          "/books/7?format=json",
+		StatusCode:   500,
+		Error:        true,
+		ErrorMessage: "book store unavailable",
+		Calls: []Call{
+			{
+				Type: callTypeDB, SpanId: "0000000000000003", ParentSpanId: "0000000000000002", Name: "SELECT books",
+				StartTime: startTime, Duration: 10, Peer: "mysql.default:3306",
+				DbSystem: "mysql", DbName: "library", Sql: "SELECT * FROM books WHERE id = ?",
+			},
+			{
+				Type: callTypeHTTP, SpanId: "0000000000000004", ParentSpanId: "0000000000000001", Name: "GET",
+				StartTime: startTime, Duration: 10, Peer: "stock:8080", Method: "GET",
+				Url: "http://stock:8080/stock/7", StatusCode: 503, Error: true, ErrorMessage: "connection refused",
+			},
+			{
+				Type: callTypeMessaging, SpanId: "0000000000000005", ParentSpanId: "0000000000000001", Name: "orders publish",
+				StartTime: startTime, Duration: 10,
+			},
+			{
+				Type: callTypeRPC, SpanId: "0000000000000006", ParentSpanId: "0000000000000001", Name: "StockService/Reserve",
+				StartTime: startTime, Duration: 10,
+			},
+		},
+	}, transaction)
+}
+
+func TestTraceTransformSpanKinds(t *testing.T) {
+	// span 是批次中的一个 span，parent 为 0 表示没有父 span
+	type span struct {
+		kind   ptrace.SpanKind
+		id     byte
+		parent byte
+	}
+	tests := []struct {
+		name  string
+		spans []span
+		// 每个事务的 span ID 最后一个字节及其调用
+		wantTransactions map[string][]string
+		wantPartial      []string
+	}{
+		{
+			name:             "server span",
+			spans:            []span{{kind: ptrace.SpanKindServer, id: 1, parent: 9}},
+			wantTransactions: map[string][]string{"0000000000000001": nil},
+		},
+		{
+			name:             "consumer span",
+			spans:            []span{{kind: ptrace.SpanKindConsumer, id: 1, parent: 9}, {kind: ptrace.SpanKindClient, id: 2, parent: 1}},
+			wantTransactions: map[string][]string{"0000000000000001": {"0000000000000002"}},
+		},
+		{
+			name:             "root internal span",
+			spans:            []span{{kind: ptrace.SpanKindInternal, id: 1}, {kind: ptrace.SpanKindClient, id: 2, parent: 1}},
+			wantTransactions: map[string][]string{"0000000000000001": {"0000000000000002"}},
+		},
+		{
+			name:             "internal span with a parent is not a transaction",
+			spans:            []span{{kind: ptrace.SpanKindInternal, id: 1, parent: 9}},
+			wantTransactions: map[string][]string{},
+		},
+		{
+			name:             "calls whose entry span is in another batch",
+			spans:            []span{{kind: ptrace.SpanKindInternal, id: 1, parent: 9}, {kind: ptrace.SpanKindClient, id: 2, parent: 1}},
+			wantTransactions: map[string][]string{"0000000000000009": {"0000000000000002"}},
+			wantPartial:      []string{"0000000000000009"},
+		},
+		{
+			name:             "client span without a parent is dropped",
+			spans:            []span{{kind: ptrace.SpanKindClient, id: 1}},
+			wantTransactions: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td, spans := newTestTraces(map[string]any{"service.instance.id": "0b5c"})
+			for _, s := range tt.spans {
+				appendTestSpan(spans, s.kind, s.id, s.parent, "span", nil)
+			}
+			requests, err := newTestTraceConverter(t).traceTransform(td)
+			require.NoError(t, err)
+
+			transactions := make(map[string][]string)
+			var partial []string
+			for _, data := range decodeTestData(t, requests) {
+				for _, transaction := range data.LogMessage.Transactions {
+					var calls []string
+					for _, call := range transaction.Calls {
+						calls = append(calls, call.SpanId)
+					}
+					transactions[transaction.SpanId] = calls
+					if transaction.Partial {
+						partial = append(partial, transaction.SpanId)
+					}
+				}
+			}
+			assert.Equal(t, tt.wantTransactions, transactions)
+			assert.Equal(t, tt.wantPartial, partial)
+		})
+	}
+}
+
+func TestTraceTransformPerResource(t *testing.T) {
+	td := ptrace.NewTraces()
+	for _, instance := range []string{"a", "b", "a"} {
+		resourceSpan := td.ResourceSpans().AppendEmpty()
+		resourceSpan.Resource().Attributes().PutStr("service.instance.id", instance)
+		appendTestSpan(resourceSpan.ScopeSpans().AppendEmpty().Spans(), ptrace.SpanKindServer, byte(td.ResourceSpans().Len()), 0, "span", nil)
+	}
+	requests, err := newTestTraceConverter(t).traceTransform(td)
+	require.NoError(t, err)
+	datas := decodeTestData(t, requests)
+	require.Len(t, datas, 2)
+	assert.Len(t, datas[0].LogMessage.Transactions, 2)
+	assert.Len(t, datas[1].LogMessage.Transactions, 1)
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configretry"
@@ -29,6 +30,38 @@ type Config struct {
 	BatcherConfig exporterbatcher.Config `mapstructure:"batcher"`
 
 	configgrpc.ClientConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Identity configures how agentId and multiAgentId of JVM payloads are built.
+	Identity jvm.IdentityConfig `mapstructure:"identity"`
+
+	// NameMapping configures how memory pool and garbage collector names are reported.
+	NameMapping jvm.NameMappingConfig `mapstructure:"name_mapping"`
+
+	// CPU configures how the CPU section of JVM payloads is computed.
+	CPU jvm.CPUConfig `mapstructure:"cpu"`
+
+	// Input configures which instrumentation scopes and metric naming dialects are converted.
+	Input jvm.InputConfig `mapstructure:"input"`
+
+	// LeakDetection configures when database connection pools are reported as leak suspects.
+	LeakDetection jvm.LeakDetectionConfig `mapstructure:"leak_detection"`
+
+	// TimestampFormat is the format of appStartTime and creationTime: "unix_milli" (default),
+	// "unix", "rfc3339" or a Go time layout such as "2006-01-02 15:04:05".
+	TimestampFormat string `mapstructure:"timestamp_format"`
+
+	// Health configures the rules that compute the status field of JVM payloads.
+	Health jvm.HealthConfig `mapstructure:"health"`
+
+	// Assembly configures how metrics of the same JVM from different batches are combined
+	// into one snapshot per interval.
+	Assembly jvm.AssemblyConfig `mapstructure:"assembly"`
+
+	// Offline configures how JVMs that stop reporting are reported offline.
+	Offline jvm.OfflineConfig `mapstructure:"offline"`
+
+	// Spill configures persisting converted payloads to disk while the backend is unavailable.
+	Spill jvm.SpillConfig `mapstructure:"spill"`
 }
 
 func (c *Config) Validate() error {
@@ -46,6 +79,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf(`invalid port "%s"`, port)
 	}
 
+	converterConfig := c.converterConfig()
+	if err := converterConfig.Validate(); err != nil {
+		return err
+	}
+	if err := c.Assembly.Validate(); err != nil {
+		return fmt.Errorf("assembly: %w", err)
+	}
+	if err := c.Offline.Validate(); err != nil {
+		return fmt.Errorf("offline: %w", err)
+	}
+	if err := c.Spill.Validate(); err != nil {
+		return fmt.Errorf("spill: %w", err)
+	}
+
 	return nil
 }
 
@@ -63,4 +110,17 @@ func (c *Config) sanitizedEndpoint() string {
 	}
 }
 
+// converterConfig 返回共享转换器使用的配置
+func (c *Config) converterConfig() jvm.ConverterConfig {
+	return jvm.ConverterConfig{
+		Identity:        c.Identity,
+		NameMapping:     c.NameMapping,
+		CPU:             c.CPU,
+		Input:           c.Input,
+		LeakDetection:   c.LeakDetection,
+		TimestampFormat: c.TimestampFormat,
+		Health:          c.Health,
+	}
+}
+
 var _ component.Config = (*Config)(nil)
@@ -0,0 +1,72 @@
+package jvmxexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*Config) {}},
+		{name: "dns endpoint", modify: func(cfg *Config) { cfg.Endpoint = "dns:///localhost:4317" }},
+		{
+			name:    "no endpoint",
+			modify:  func(cfg *Config) { cfg.Endpoint = "" },
+			wantErr: `requires a non-empty "endpoint"`,
+		},
+		{
+			name:    "no port",
+			modify:  func(cfg *Config) { cfg.Endpoint = "http://localhost" },
+			wantErr: "address localhost: missing port in address",
+		},
+		{
+			name:    "invalid port",
+			modify:  func(cfg *Config) { cfg.Endpoint = "localhost:grpc" },
+			wantErr: `invalid port "grpc"`,
+		},
+		{
+			name:    "unclosed agent id placeholder",
+			modify:  func(cfg *Config) { cfg.Identity.AgentID = "${service.name" },
+			wantErr: `identity: invalid agent_id template: unclosed placeholder in "${service.name"`,
+		},
+		{
+			name:    "empty multi agent id attribute",
+			modify:  func(cfg *Config) { cfg.Identity.MultiAgentID = "${:-bookdemo}" },
+			wantErr: "identity: invalid multi_agent_id template: empty attribute name in placeholder",
+		},
+		{
+			name:    "invalid assembly",
+			modify:  func(cfg *Config) { cfg.Assembly.Interval = -time.Second },
+			wantErr: "assembly: interval must not be negative",
+		},
+		{
+			name:    "invalid offline",
+			modify:  func(cfg *Config) { cfg.Offline.Timeout = -time.Second },
+			wantErr: "offline: timeout must not be negative",
+		},
+		{
+			name:    "invalid spill",
+			modify:  func(cfg *Config) { cfg.Spill.Directory, cfg.Spill.ReplayInterval = t.TempDir(), 0 },
+			wantErr: "spill: replay_interval must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "localhost:4317"
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package jvmxexporter // import "go.opentelemetry.io/collector/exporter/otlpexpor
 import (
 	"context"
 
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -56,11 +57,21 @@ func createDefaultConfig() component.Config {
 	clientCfg.BalancerName = ""
 
 	return &Config{
-		TimeoutConfig: exporterhelper.NewDefaultTimeoutConfig(),
-		RetryConfig:   configretry.NewDefaultBackOffConfig(),
-		QueueConfig:   exporterhelper.NewDefaultQueueConfig(),
-		BatcherConfig: batcherCfg,
-		ClientConfig:  clientCfg,
+		TimeoutConfig:   exporterhelper.NewDefaultTimeoutConfig(),
+		RetryConfig:     configretry.NewDefaultBackOffConfig(),
+		QueueConfig:     exporterhelper.NewDefaultQueueConfig(),
+		BatcherConfig:   batcherCfg,
+		ClientConfig:    clientCfg,
+		Identity:        jvm.NewDefaultIdentityConfig(),
+		NameMapping:     jvm.NewDefaultNameMappingConfig(),
+		CPU:             jvm.NewDefaultCPUConfig(),
+		Input:           jvm.NewDefaultInputConfig(),
+		LeakDetection:   jvm.NewDefaultLeakDetectionConfig(),
+		TimestampFormat: jvm.DefaultTimestampFormat,
+		Health:          jvm.NewDefaultHealthConfig(),
+		Assembly:        jvm.NewDefaultAssemblyConfig(),
+		Offline:         jvm.NewDefaultOfflineConfig(),
+		Spill:           jvm.NewDefaultSpillConfig(),
 	}
 }
 
@@ -69,7 +80,10 @@ func createTraces(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Traces, error) {
-	oce := newExporter(cfg, set)
+	oce, err := newExporter(cfg, set)
+	if err != nil {
+		return nil, err
+	}
 	oCfg := cfg.(*Config)
 	return exporterhelper.NewTraces(ctx, set, cfg,
 		oce.pushTraces,
@@ -88,8 +102,23 @@ func createMetrics(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Metrics, error) {
-	oce := newExporter(cfg, set)
+	oce, err := newExporter(cfg, set)
+	if err != nil {
+		return nil, err
+	}
 	oCfg := cfg.(*Config)
+	if oCfg.Assembly.Interval > 0 {
+		oce.assembler = jvm.NewAssembler(oCfg.Assembly, oce.converter, oce.flushMetrics, set.Logger)
+	}
+	if oCfg.Offline.Timeout > 0 {
+		oce.offline = jvm.NewOfflineTracker(oCfg.Offline, oce.sendOffline, set.Logger)
+		oce.converter.TrackOffline(oce.offline)
+	}
+	if oCfg.Spill.Directory != "" {
+		if oce.spill, err = jvm.NewSpillQueue(oCfg.Spill, set.ID, "metrics", oce.sendPayload, set.TelemetrySettings); err != nil {
+			return nil, err
+		}
+	}
 	return exporterhelper.NewMetrics(ctx, set, cfg,
 		oce.pushMetrics,
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
@@ -107,7 +136,10 @@ func createLogs(
 	set exporter.Settings,
 	cfg component.Config,
 ) (exporter.Logs, error) {
-	oce := newExporter(cfg, set)
+	oce, err := newExporter(cfg, set)
+	if err != nil {
+		return nil, err
+	}
 	oCfg := cfg.(*Config)
 	return exporterhelper.NewLogs(ctx, set, cfg,
 		oce.pushLogs,
@@ -126,7 +158,10 @@ func createProfilesExporter(
 	set exporter.Settings,
 	cfg component.Config,
 ) (xexporter.Profiles, error) {
-	oce := newExporter(cfg, set)
+	oce, err := newExporter(cfg, set)
+	if err != nil {
+		return nil, err
+	}
 	oCfg := cfg.(*Config)
 	return xexporterhelper.NewProfilesExporter(ctx, set, cfg,
 		oce.pushProfiles,
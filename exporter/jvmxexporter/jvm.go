@@ -7,8 +7,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
 	"github.com/Liuxiaoxxz/third-party/grpc/metrics"
 	"runtime"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -16,12 +18,12 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -31,6 +33,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 )
 
+// 落盘请求体的类型，gRPC 导出器只落盘转换后的 JVM 指标
+const payloadKindMetrics = "metrics"
+
 type baseExporter struct {
 	// Input configuration.
 	config *Config
@@ -44,19 +49,31 @@ type baseExporter struct {
 	metadata        metadata.MD
 	callOptions     []grpc.CallOption
 
-	settings component.TelemetrySettings
+	settings  component.TelemetrySettings
+	converter *metricConverter
+	// assembler 不为空时指标先按 JVM 缓存，再按周期发送
+	assembler *jvm.Assembler
+	// offline 不为空时跟踪各 JVM 的最后上报时间
+	offline *jvm.OfflineTracker
+	// spill 不为空时发送失败的请求体落盘并在后端恢复后回放
+	spill *jvm.SpillQueue
 
 	// Default user-agent header.
 	userAgent string
 }
 
-func newExporter(cfg component.Config, set exporter.Settings) *baseExporter {
+func newExporter(cfg component.Config, set exporter.Settings) (*baseExporter, error) {
 	oCfg := cfg.(*Config)
 
+	converter, err := newMetricConverter(oCfg, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
 	userAgent := fmt.Sprintf("%s/%s (%s/%s)",
 		set.BuildInfo.Description, set.BuildInfo.Version, runtime.GOOS, runtime.GOARCH)
 
-	return &baseExporter{config: oCfg, settings: set.TelemetrySettings, userAgent: userAgent}
+	return &baseExporter{config: oCfg, settings: set.TelemetrySettings, userAgent: userAgent, converter: converter}, nil
 }
 
 // start actually creates the gRPC connection. The client construction is deferred till this point as this
@@ -78,15 +95,37 @@ func (e *baseExporter) start(ctx context.Context, host component.Host) (err erro
 	e.callOptions = []grpc.CallOption{
 		grpc.WaitForReady(e.config.ClientConfig.WaitForReady),
 	}
+	if e.spill != nil {
+		if err = e.spill.Start(); err != nil {
+			return err
+		}
+	}
+	if e.assembler != nil {
+		e.assembler.Start()
+	}
+	if e.offline != nil {
+		e.offline.Start()
+	}
 
 	return
 }
 
-func (e *baseExporter) shutdown(context.Context) error {
+func (e *baseExporter) shutdown(ctx context.Context) error {
+	// 最后一份快照需要在关闭连接前发送
+	var err error
+	if e.offline != nil {
+		err = e.offline.Shutdown(ctx)
+	}
+	if e.assembler != nil {
+		err = errors.Join(err, e.assembler.Shutdown(ctx))
+	}
+	if e.spill != nil {
+		err = errors.Join(err, e.spill.Shutdown(ctx))
+	}
 	if e.clientConn != nil {
-		return e.clientConn.Close()
+		err = errors.Join(err, e.clientConn.Close())
 	}
-	return nil
+	return err
 }
 
 func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
@@ -106,25 +145,92 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 }
 
 func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
-	req := &metrics.ExportRequest{
-		Orig:  nil,
-		State: 0,
+	if e.assembler != nil {
+		e.assembler.Add(md, time.Now())
+		return nil
 	}
-	e.settings.Logger.Info("jvm grpc pushMetrics ....")
-	orig, err := metricTransform(ctx, md, e.settings.Logger)
-	if err != nil {
-		panic("error transforming metrics")
+	return e.sendMetrics(ctx, md)
+}
+
+// sendMetrics converts the metrics and sends one request per JVM instance.
+func (e *baseExporter) sendMetrics(ctx context.Context, md pmetric.Metrics) error {
+	e.settings.Logger.Debug("jvm grpc pushMetrics ....")
+	// Each JVM instance is sent as its own request so that the backend never sees merged data.
+	for _, orig := range e.converter.metricTransform(md) {
+		if err := e.deliver(ctx, orig); err != nil {
+			return err
+		}
 	}
-	req.Orig = orig
-	resp, err := e.metricExporter.Export(ctx, req, e.callOptions...)
+	return nil
+}
 
+// flushMetrics converts an assembled snapshot and sends it. The snapshot does not pass through the
+// sending queue, so each converted request is retried as configured by retry_on_failure. Retrying
+// the converted requests instead of the snapshot keeps delta metrics from being counted twice.
+func (e *baseExporter) flushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	for _, orig := range e.converter.metricTransform(md) {
+		if err := jvm.Retry(ctx, e.config.RetryConfig, func() error { return e.deliver(ctx, orig) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendOffline sends the final offline message of each JVM.
+func (e *baseExporter) sendOffline(ctx context.Context, agents []jvm.OfflineAgent) error {
+	for _, orig := range e.converter.offlineTransform(agents, time.Now()) {
+		if err := e.deliver(ctx, orig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends one converted JVM payload. With spilling enabled a payload that fails with a
+// retryable error is stored on disk and replayed later, and while stored payloads are pending
+// new ones are queued behind them so that the backend receives them in order.
+func (e *baseExporter) deliver(ctx context.Context, orig *metrics.ExportMetricsServiceRequest) error {
+	if e.spill == nil {
+		return e.exportMetrics(ctx, orig)
+	}
+	if e.spill.Pending() == 0 {
+		err := e.exportMetrics(ctx, orig)
+		if err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+		e.settings.Logger.Debug("Failed to send payload, spilling it to disk", zap.String("agentId", orig.AgentId), zap.Error(err))
+	}
+	payload, err := proto.Marshal(orig)
 	if err != nil {
-		e.settings.Logger.Error("jvm grpc pushMetrics failed", zap.Error(err))
+		return consumererror.NewPermanent(err)
 	}
+	return e.spill.Put(payloadKindMetrics, payload, time.Now())
+}
 
-	state := resp.State
-	if state == 0 {
-		return errors.New("metrics state is zero")
+// sendPayload sends a spilled payload.
+func (e *baseExporter) sendPayload(ctx context.Context, kind string, payload []byte) error {
+	if kind != payloadKindMetrics {
+		return consumererror.NewPermanent(fmt.Errorf("unknown payload kind %q", kind))
+	}
+	orig := &metrics.ExportMetricsServiceRequest{}
+	if err := proto.Unmarshal(payload, orig); err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	return e.exportMetrics(ctx, orig)
+}
+
+// exportMetrics sends the payload of one JVM instance.
+func (e *baseExporter) exportMetrics(ctx context.Context, orig *metrics.ExportMetricsServiceRequest) error {
+	req := &metrics.ExportRequest{
+		Orig:  orig,
+		State: 0,
+	}
+	resp, respErr := e.metricExporter.Export(ctx, req, e.callOptions...)
+	if err := processError(respErr); err != nil {
+		return err
+	}
+	if resp.GetState() == 0 {
+		return fmt.Errorf("metrics state is zero for agent %q", orig.AgentId)
 	}
 	return nil
 }
@@ -186,7 +292,7 @@ func processError(err error) error {
 	throttleDuration := retryInfo.GetRetryDelay().AsDuration()
 	if throttleDuration != 0 {
 		// We are throttled. Wait before retrying as requested by the server.
-		return exporterhelper.NewThrottleRetry(err, throttleDuration)
+		return jvm.NewThrottleRetry(err, throttleDuration)
 	}
 
 	// Need to retry.
@@ -0,0 +1,242 @@
+package jvmxexporter
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Liuxiaoxxz/third-party/exporter/internal/jvm"
+	"github.com/Liuxiaoxxz/third-party/grpc/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testMetricsServer 记录收到的 JVM 指标请求，依次用 errs 中的错误应答，之后返回 state
+type testMetricsServer struct {
+	metrics.UnimplementedGrpcServer
+	mu       sync.Mutex
+	requests []*metrics.ExportMetricsServiceRequest
+	errs     []error
+	state    int32
+}
+
+func (s *testMetricsServer) Export(_ context.Context, req *metrics.ExportRequest) (*metrics.ExportResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, err
+	}
+	s.requests = append(s.requests, req.GetOrig())
+	return &metrics.ExportResponse{State: s.state}, nil
+}
+
+func (s *testMetricsServer) received() []*metrics.ExportMetricsServiceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*metrics.ExportMetricsServiceRequest(nil), s.requests...)
+}
+
+// newTestConfig 启动 server 并返回发送到该 server 的配置，agentId 取 service.instance.id
+func newTestConfig(t *testing.T, server *testMetricsServer) *Config {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	metrics.RegisterGrpcServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientConfig.Endpoint = listener.Addr().String()
+	cfg.ClientConfig.TLSSetting.Insecure = true
+	cfg.Identity.AgentID = "${service.instance.id}"
+	return cfg
+}
+
+// nopHost 是不提供扩展的 component.Host
+type nopHost struct{}
+
+func (nopHost) GetExtensions() map[component.ID]component.Component { return nil }
+
+func newTestExporter(t *testing.T, cfg *Config) *baseExporter {
+	set := exporter.Settings{
+		ID:                component.MustNewID("jvm"),
+		TelemetrySettings: component.TelemetrySettings{Logger: zap.NewNop()},
+		BuildInfo:         component.NewDefaultBuildInfo(),
+	}
+	e, err := newExporter(cfg, set)
+	require.NoError(t, err)
+	return e
+}
+
+// startTestExporter 启动导出器，测试结束时关闭
+func startTestExporter(t *testing.T, e *baseExporter) {
+	require.NoError(t, e.start(context.Background(), nopHost{}))
+	t.Cleanup(func() { require.NoError(t, e.shutdown(context.Background())) })
+}
+
+// newTestMetrics 为每组资源属性生成一个带 jvm.memory.used 的资源
+func newTestMetrics(resources ...map[string]any) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for i, attributes := range resources {
+		resourceMetric := md.ResourceMetrics().AppendEmpty()
+		_ = resourceMetric.Resource().Attributes().FromRaw(attributes)
+		scopeMetric := resourceMetric.ScopeMetrics().AppendEmpty()
+		scopeMetric.Scope().SetName("io.opentelemetry.runtime-telemetry-java17")
+		metric := scopeMetric.Metrics().AppendEmpty()
+		metric.SetName(jvm.JVM_MEMORY_USED)
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dataPoint := sum.DataPoints().AppendEmpty()
+		dataPoint.Attributes().PutStr(jvm.JVM_MEMORY_POOL_NAME, "G1 Eden Space")
+		dataPoint.SetIntValue(int64(i+1) * 1024)
+	}
+	return md
+}
+
+func TestPushMetrics(t *testing.T) {
+	server := &testMetricsServer{state: 1}
+	cfg := newTestConfig(t, server)
+	cfg.Identity.AgentID = "${service.name}-${host.name}-${process.pid}"
+	e := newTestExporter(t, cfg)
+	startTestExporter(t, e)
+
+	md := newTestMetrics(
+		map[string]any{"service.name": "bookdemo", "host.name": "pod-a", "process.pid": int64(4242), "container.id": "3f4e"},
+		map[string]any{"service.name": "bookdemo", "host.name": "pod-b", "process.pid": int64(4242)},
+		// 批次中没有 JVM 指标的资源不发送
+		map[string]any{"service.name": "gateway"},
+	)
+	md.ResourceMetrics().At(2).ScopeMetrics().At(0).Scope().SetName("io.opentelemetry.http")
+	require.NoError(t, e.pushMetrics(context.Background(), md))
+
+	requests := server.received()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "bookdemo-pod-a-4242", requests[0].AgentId)
+	assert.Equal(t, "bookdemo-pod-a-4242", requests[0].MultiAgentId)
+	assert.Equal(t, "4242", requests[0].Pid)
+	assert.True(t, requests[0].Docker)
+	assert.Equal(t, int64(1024), requests[0].MemoryPool.MemoryUsages["G1EdenSpace"].Used)
+	assert.Equal(t, "bookdemo-pod-b-4242", requests[1].AgentId)
+	assert.False(t, requests[1].Docker)
+	assert.Equal(t, int64(2048), requests[1].MemoryPool.MemoryUsages["G1EdenSpace"].Used)
+}
+
+func TestPushMetricsErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		server        *testMetricsServer
+		wantErr       string
+		wantPermanent bool
+	}{
+		{
+			name:    "zero state",
+			server:  &testMetricsServer{},
+			wantErr: `metrics state is zero for agent "0b5c"`,
+		},
+		{
+			name:    "unavailable",
+			server:  &testMetricsServer{errs: []error{status.Error(codes.Unavailable, "backend unavailable")}},
+			wantErr: "rpc error: code = Unavailable desc = backend unavailable",
+		},
+		{
+			name:          "invalid argument",
+			server:        &testMetricsServer{errs: []error{status.Error(codes.InvalidArgument, "bad request")}},
+			wantErr:       "Permanent error: rpc error: code = InvalidArgument desc = bad request",
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestExporter(t, newTestConfig(t, tt.server))
+			startTestExporter(t, e)
+			err := e.pushMetrics(context.Background(), newTestMetrics(map[string]any{"service.instance.id": "0b5c"}))
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantPermanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func TestPushMetricsAssembled(t *testing.T) {
+	server := &testMetricsServer{state: 1}
+	cfg := newTestConfig(t, server)
+	cfg.Assembly.Interval = time.Hour
+	e := newTestExporter(t, cfg)
+	e.assembler = jvm.NewAssembler(cfg.Assembly, e.converter, e.flushMetrics, zap.NewNop())
+	require.NoError(t, e.start(context.Background(), nopHost{}))
+
+	instance := map[string]any{"service.instance.id": "0b5c"}
+	require.NoError(t, e.pushMetrics(context.Background(), newTestMetrics(instance)))
+	require.NoError(t, e.pushMetrics(context.Background(), newTestMetrics(instance, instance)))
+	assert.Empty(t, server.received())
+
+	// 关闭时发送最后一份快照，同一 JVM 的指标合并为一个请求
+	require.NoError(t, e.shutdown(context.Background()))
+	requests := server.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "0b5c", requests[0].AgentId)
+	assert.Equal(t, int64(2048), requests[0].MemoryPool.MemoryUsages["G1EdenSpace"].Used)
+}
+
+func TestFlushMetricsRetries(t *testing.T) {
+	server := &testMetricsServer{state: 1, errs: []error{status.Error(codes.Unavailable, "backend unavailable")}}
+	cfg := newTestConfig(t, server)
+	cfg.RetryConfig.InitialInterval = time.Millisecond
+	e := newTestExporter(t, cfg)
+	startTestExporter(t, e)
+
+	require.NoError(t, e.flushMetrics(context.Background(), newTestMetrics(map[string]any{"service.instance.id": "0b5c"})))
+	assert.Len(t, server.received(), 1)
+}
+
+func TestSendOffline(t *testing.T) {
+	server := &testMetricsServer{state: 1}
+	e := newTestExporter(t, newTestConfig(t, server))
+	startTestExporter(t, e)
+
+	require.NoError(t, e.sendOffline(context.Background(), []jvm.OfflineAgent{{
+		Key:          "0b5c",
+		AgentID:      "bookdemo-0b5c",
+		MultiAgentID: "bookdemo",
+		AppName:      "bookdemo",
+		Pid:          "4242",
+		LastSeen:     time.Now().Add(-time.Minute),
+	}}))
+	requests := server.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "bookdemo-0b5c", requests[0].AgentId)
+	assert.Equal(t, "bookdemo", requests[0].MultiAgentId)
+	assert.Equal(t, "4242", requests[0].Pid)
+	assert.Equal(t, int32(jvm.HealthStatusOffline), requests[0].Status)
+}
+
+func TestDeliverSpill(t *testing.T) {
+	server := &testMetricsServer{state: 1, errs: []error{status.Error(codes.Unavailable, "backend unavailable")}}
+	cfg := newTestConfig(t, server)
+	cfg.Spill.Directory = t.TempDir()
+	cfg.Spill.ReplayInterval = 10 * time.Millisecond
+	e := newTestExporter(t, cfg)
+	var err error
+	e.spill, err = jvm.NewSpillQueue(cfg.Spill, component.MustNewID("jvm"), "metrics", e.sendPayload, component.TelemetrySettings{Logger: zap.NewNop()})
+	require.NoError(t, err)
+	startTestExporter(t, e)
+
+	// 第一个请求发送失败后落盘，之后的请求排在它后面，回放时按顺序发送
+	require.NoError(t, e.pushMetrics(context.Background(), newTestMetrics(map[string]any{"service.instance.id": "a"})))
+	require.NoError(t, e.pushMetrics(context.Background(), newTestMetrics(map[string]any{"service.instance.id": "b"})))
+	assert.Eventually(t, func() bool { return len(server.received()) == 2 && e.spill.Pending() == 0 }, 5*time.Second, 10*time.Millisecond)
+	requests := server.received()
+	assert.Equal(t, "a", requests[0].AgentId)
+	assert.Equal(t, "b", requests[1].AgentId)
+}
@@ -1,3 +0,0 @@
-module github.com/Liuxiaoxxz/third-party/grpc/metrics
-
-go 1.23.6
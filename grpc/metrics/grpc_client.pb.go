@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.2
+// 	protoc-gen-go v1.36.5
 // 	protoc        v3.20.3
 // source: grpc_client.proto
 
@@ -12,6 +12,7 @@ import (
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -22,12 +23,11 @@ const (
 )
 
 type ExportRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Orig          *ExportMetricsServiceRequest `protobuf:"bytes,1,opt,name=orig,proto3" json:"orig,omitempty"`
+	State         int32                        `protobuf:"varint,2,opt,name=state,proto3" json:"state,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Orig  *ExportMetricsServiceRequest `protobuf:"bytes,1,opt,name=orig,proto3" json:"orig,omitempty"`
-	State int32                        `protobuf:"varint,2,opt,name=state,proto3" json:"state,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExportRequest) Reset() {
@@ -75,12 +75,11 @@ func (x *ExportRequest) GetState() int32 {
 }
 
 type ExportResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Orig          *ExportMetricsServiceRequest `protobuf:"bytes,1,opt,name=orig,proto3" json:"orig,omitempty"`
+	State         int32                        `protobuf:"varint,2,opt,name=state,proto3" json:"state,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Orig  *ExportMetricsServiceRequest `protobuf:"bytes,1,opt,name=orig,proto3" json:"orig,omitempty"`
-	State int32                        `protobuf:"varint,2,opt,name=state,proto3" json:"state,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExportResponse) Reset() {
@@ -129,14 +128,13 @@ func (x *ExportResponse) GetState() int32 {
 
 // 定义 CPU 结构体
 type CPU struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProcessCpu    float64                `protobuf:"fixed64,1,opt,name=processCpu,proto3" json:"processCpu,omitempty"`
+	AvgSystemCpu  float64                `protobuf:"fixed64,2,opt,name=avgSystemCpu,proto3" json:"avgSystemCpu,omitempty"`
+	SystemCpu     float64                `protobuf:"fixed64,3,opt,name=systemCpu,proto3" json:"systemCpu,omitempty"`
+	AvgProcessCpu float64                `protobuf:"fixed64,4,opt,name=avgProcessCpu,proto3" json:"avgProcessCpu,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	ProcessCpu    float64 `protobuf:"fixed64,1,opt,name=processCpu,proto3" json:"processCpu,omitempty"`
-	AvgSystemCpu  float64 `protobuf:"fixed64,2,opt,name=avgSystemCpu,proto3" json:"avgSystemCpu,omitempty"`
-	SystemCpu     float64 `protobuf:"fixed64,3,opt,name=systemCpu,proto3" json:"systemCpu,omitempty"`
-	AvgProcessCpu float64 `protobuf:"fixed64,4,opt,name=avgProcessCpu,proto3" json:"avgProcessCpu,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CPU) Reset() {
@@ -197,19 +195,119 @@ func (x *CPU) GetAvgProcessCpu() float64 {
 	return 0
 }
 
+// 定义 ThreadInfo 结构体，描述一个阻塞或死锁的线程
+type ThreadInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      int64                  `protobuf:"varint,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	ThreadName    string                 `protobuf:"bytes,2,opt,name=threadName,proto3" json:"threadName,omitempty"`
+	ThreadState   string                 `protobuf:"bytes,3,opt,name=threadState,proto3" json:"threadState,omitempty"`
+	LockName      string                 `protobuf:"bytes,4,opt,name=lockName,proto3" json:"lockName,omitempty"`
+	LockOwnerId   int64                  `protobuf:"varint,5,opt,name=lockOwnerId,proto3" json:"lockOwnerId,omitempty"`
+	LockOwnerName string                 `protobuf:"bytes,6,opt,name=lockOwnerName,proto3" json:"lockOwnerName,omitempty"`
+	Deadlocked    bool                   `protobuf:"varint,7,opt,name=deadlocked,proto3" json:"deadlocked,omitempty"`
+	StackTrace    []string               `protobuf:"bytes,8,rep,name=stackTrace,proto3" json:"stackTrace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThreadInfo) Reset() {
+	*x = ThreadInfo{}
+	mi := &file_grpc_client_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadInfo) ProtoMessage() {}
+
+func (x *ThreadInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_client_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadInfo.ProtoReflect.Descriptor instead.
+func (*ThreadInfo) Descriptor() ([]byte, []int) {
+	return file_grpc_client_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ThreadInfo) GetThreadId() int64 {
+	if x != nil {
+		return x.ThreadId
+	}
+	return 0
+}
+
+func (x *ThreadInfo) GetThreadName() string {
+	if x != nil {
+		return x.ThreadName
+	}
+	return ""
+}
+
+func (x *ThreadInfo) GetThreadState() string {
+	if x != nil {
+		return x.ThreadState
+	}
+	return ""
+}
+
+func (x *ThreadInfo) GetLockName() string {
+	if x != nil {
+		return x.LockName
+	}
+	return ""
+}
+
+func (x *ThreadInfo) GetLockOwnerId() int64 {
+	if x != nil {
+		return x.LockOwnerId
+	}
+	return 0
+}
+
+func (x *ThreadInfo) GetLockOwnerName() string {
+	if x != nil {
+		return x.LockOwnerName
+	}
+	return ""
+}
+
+func (x *ThreadInfo) GetDeadlocked() bool {
+	if x != nil {
+		return x.Deadlocked
+	}
+	return false
+}
+
+func (x *ThreadInfo) GetStackTrace() []string {
+	if x != nil {
+		return x.StackTrace
+	}
+	return nil
+}
+
 // 定义 ThreadInfos 结构体
 type ThreadInfos struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LockNames     []string               `protobuf:"bytes,1,rep,name=lockNames,proto3" json:"lockNames,omitempty"`
+	ThreadInfo    []*ThreadInfo          `protobuf:"bytes,3,rep,name=threadInfo,proto3" json:"threadInfo,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	LockNames  []string `protobuf:"bytes,1,rep,name=lockNames,proto3" json:"lockNames,omitempty"`
-	ThreadInfo []string `protobuf:"bytes,2,rep,name=threadInfo,proto3" json:"threadInfo,omitempty"` // 这里使用 repeated repeated 来表示二维数组
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ThreadInfos) Reset() {
 	*x = ThreadInfos{}
-	mi := &file_grpc_client_proto_msgTypes[3]
+	mi := &file_grpc_client_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -221,7 +319,7 @@ func (x *ThreadInfos) String() string {
 func (*ThreadInfos) ProtoMessage() {}
 
 func (x *ThreadInfos) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[3]
+	mi := &file_grpc_client_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -234,7 +332,7 @@ func (x *ThreadInfos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThreadInfos.ProtoReflect.Descriptor instead.
 func (*ThreadInfos) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{3}
+	return file_grpc_client_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ThreadInfos) GetLockNames() []string {
@@ -244,7 +342,7 @@ func (x *ThreadInfos) GetLockNames() []string {
 	return nil
 }
 
-func (x *ThreadInfos) GetThreadInfo() []string {
+func (x *ThreadInfos) GetThreadInfo() []*ThreadInfo {
 	if x != nil {
 		return x.ThreadInfo
 	}
@@ -253,20 +351,21 @@ func (x *ThreadInfos) GetThreadInfo() []string {
 
 // 定义 Thread 结构体
 type Thread struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	ThreadCount             int64                  `protobuf:"varint,1,opt,name=threadCount,proto3" json:"threadCount,omitempty"`
+	ThreadInfos             *ThreadInfos           `protobuf:"bytes,2,opt,name=threadInfos,proto3" json:"threadInfos,omitempty"`
+	TotalStartedThreadCount int64                  `protobuf:"varint,3,opt,name=totalStartedThreadCount,proto3" json:"totalStartedThreadCount,omitempty"`
+	PeakThreadCount         int64                  `protobuf:"varint,4,opt,name=peakThreadCount,proto3" json:"peakThreadCount,omitempty"`
+	DeamonThreadCount       int64                  `protobuf:"varint,5,opt,name=deamonThreadCount,proto3" json:"deamonThreadCount,omitempty"`
+	// 按 java.lang.Thread.State 统计的线程数
+	StateCounts   map[string]int64 `protobuf:"bytes,6,rep,name=stateCounts,proto3" json:"stateCounts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
-
-	ThreadCount             int64        `protobuf:"varint,1,opt,name=threadCount,proto3" json:"threadCount,omitempty"`
-	ThreadInfos             *ThreadInfos `protobuf:"bytes,2,opt,name=threadInfos,proto3" json:"threadInfos,omitempty"`
-	TotalStartedThreadCount int32        `protobuf:"varint,3,opt,name=totalStartedThreadCount,proto3" json:"totalStartedThreadCount,omitempty"`
-	PeakThreadCount         int64        `protobuf:"varint,4,opt,name=peakThreadCount,proto3" json:"peakThreadCount,omitempty"`
-	DeamonThreadCount       int64        `protobuf:"varint,5,opt,name=deamonThreadCount,proto3" json:"deamonThreadCount,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Thread) Reset() {
 	*x = Thread{}
-	mi := &file_grpc_client_proto_msgTypes[4]
+	mi := &file_grpc_client_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -278,7 +377,7 @@ func (x *Thread) String() string {
 func (*Thread) ProtoMessage() {}
 
 func (x *Thread) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[4]
+	mi := &file_grpc_client_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,7 +390,7 @@ func (x *Thread) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Thread.ProtoReflect.Descriptor instead.
 func (*Thread) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{4}
+	return file_grpc_client_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Thread) GetThreadCount() int64 {
@@ -308,7 +407,7 @@ func (x *Thread) GetThreadInfos() *ThreadInfos {
 	return nil
 }
 
-func (x *Thread) GetTotalStartedThreadCount() int32 {
+func (x *Thread) GetTotalStartedThreadCount() int64 {
 	if x != nil {
 		return x.TotalStartedThreadCount
 	}
@@ -329,21 +428,27 @@ func (x *Thread) GetDeamonThreadCount() int64 {
 	return 0
 }
 
+func (x *Thread) GetStateCounts() map[string]int64 {
+	if x != nil {
+		return x.StateCounts
+	}
+	return nil
+}
+
 // 定义 MemoryUsage 结构体
 type MemoryUsage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Init          int64                  `protobuf:"varint,1,opt,name=init,proto3" json:"init,omitempty"`
+	Committed     int64                  `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Max           int64                  `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
+	Used          int64                  `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Init      int64 `protobuf:"varint,1,opt,name=init,proto3" json:"init,omitempty"`
-	Committed int64 `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
-	Max       int64 `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
-	Used      int64 `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MemoryUsage) Reset() {
 	*x = MemoryUsage{}
-	mi := &file_grpc_client_proto_msgTypes[5]
+	mi := &file_grpc_client_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -355,7 +460,7 @@ func (x *MemoryUsage) String() string {
 func (*MemoryUsage) ProtoMessage() {}
 
 func (x *MemoryUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[5]
+	mi := &file_grpc_client_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -368,7 +473,7 @@ func (x *MemoryUsage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MemoryUsage.ProtoReflect.Descriptor instead.
 func (*MemoryUsage) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{5}
+	return file_grpc_client_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MemoryUsage) GetInit() int64 {
@@ -401,16 +506,15 @@ func (x *MemoryUsage) GetUsed() int64 {
 
 // 定义 MemoryPool 结构体
 type MemoryPool struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	MemoryUsages  map[string]*MemoryUsage `protobuf:"bytes,1,rep,name=memoryUsages,proto3" json:"memoryUsages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
-
-	MemoryUsages map[string]*MemoryUsage `protobuf:"bytes,1,rep,name=memoryUsages,proto3" json:"memoryUsages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MemoryPool) Reset() {
 	*x = MemoryPool{}
-	mi := &file_grpc_client_proto_msgTypes[6]
+	mi := &file_grpc_client_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -422,7 +526,7 @@ func (x *MemoryPool) String() string {
 func (*MemoryPool) ProtoMessage() {}
 
 func (x *MemoryPool) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[6]
+	mi := &file_grpc_client_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +539,7 @@ func (x *MemoryPool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MemoryPool.ProtoReflect.Descriptor instead.
 func (*MemoryPool) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{6}
+	return file_grpc_client_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *MemoryPool) GetMemoryUsages() map[string]*MemoryUsage {
@@ -447,20 +551,19 @@ func (x *MemoryPool) GetMemoryUsages() map[string]*MemoryUsage {
 
 // 定义 GarbageCollectorInfo 结构体
 type GarbageCollectorInfo struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Valid           bool     `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	CollectionTime  int32    `protobuf:"varint,2,opt,name=collectionTime,proto3" json:"collectionTime,omitempty"`
-	MemoryPoolNames []string `protobuf:"bytes,3,rep,name=memoryPoolNames,proto3" json:"memoryPoolNames,omitempty"`
-	CollectionCount uint64   `protobuf:"varint,4,opt,name=collectionCount,proto3" json:"collectionCount,omitempty"`
-	Name            string   `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Valid           bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	CollectionTime  int32                  `protobuf:"varint,2,opt,name=collectionTime,proto3" json:"collectionTime,omitempty"`
+	MemoryPoolNames []string               `protobuf:"bytes,3,rep,name=memoryPoolNames,proto3" json:"memoryPoolNames,omitempty"`
+	CollectionCount uint64                 `protobuf:"varint,4,opt,name=collectionCount,proto3" json:"collectionCount,omitempty"`
+	Name            string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GarbageCollectorInfo) Reset() {
 	*x = GarbageCollectorInfo{}
-	mi := &file_grpc_client_proto_msgTypes[7]
+	mi := &file_grpc_client_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -472,7 +575,7 @@ func (x *GarbageCollectorInfo) String() string {
 func (*GarbageCollectorInfo) ProtoMessage() {}
 
 func (x *GarbageCollectorInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[7]
+	mi := &file_grpc_client_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,7 +588,7 @@ func (x *GarbageCollectorInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GarbageCollectorInfo.ProtoReflect.Descriptor instead.
 func (*GarbageCollectorInfo) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{7}
+	return file_grpc_client_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GarbageCollectorInfo) GetValid() bool {
@@ -525,16 +628,15 @@ func (x *GarbageCollectorInfo) GetName() string {
 
 // 定义 GarbageCollector 结构体
 type GarbageCollector struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	GarbageCollectors map[string]*GarbageCollectorInfo `protobuf:"bytes,1,rep,name=garbageCollectors,proto3" json:"garbageCollectors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	state             protoimpl.MessageState           `protogen:"open.v1"`
+	GarbageCollectors map[string]*GarbageCollectorInfo `protobuf:"bytes,1,rep,name=garbageCollectors,proto3" json:"garbageCollectors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GarbageCollector) Reset() {
 	*x = GarbageCollector{}
-	mi := &file_grpc_client_proto_msgTypes[8]
+	mi := &file_grpc_client_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -546,7 +648,7 @@ func (x *GarbageCollector) String() string {
 func (*GarbageCollector) ProtoMessage() {}
 
 func (x *GarbageCollector) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[8]
+	mi := &file_grpc_client_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -559,7 +661,7 @@ func (x *GarbageCollector) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GarbageCollector.ProtoReflect.Descriptor instead.
 func (*GarbageCollector) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{8}
+	return file_grpc_client_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GarbageCollector) GetGarbageCollectors() map[string]*GarbageCollectorInfo {
@@ -569,19 +671,196 @@ func (x *GarbageCollector) GetGarbageCollectors() map[string]*GarbageCollectorIn
 	return nil
 }
 
-// 定义 DatabaseConnectionMessage 结构体
-type DatabaseConnectionMessage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// 定义 DatabaseConnection 结构体，waitTime 和 useTime 为上报周期内的平均值，单位为毫秒
+type DatabaseConnection struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PoolName        string                 `protobuf:"bytes,1,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	Idle            int64                  `protobuf:"varint,2,opt,name=idle,proto3" json:"idle,omitempty"`
+	Used            int64                  `protobuf:"varint,3,opt,name=used,proto3" json:"used,omitempty"`
+	Max             int64                  `protobuf:"varint,4,opt,name=max,proto3" json:"max,omitempty"`
+	PendingRequests int64                  `protobuf:"varint,5,opt,name=pendingRequests,proto3" json:"pendingRequests,omitempty"`
+	WaitTime        float64                `protobuf:"fixed64,6,opt,name=waitTime,proto3" json:"waitTime,omitempty"`
+	UseTime         float64                `protobuf:"fixed64,7,opt,name=useTime,proto3" json:"useTime,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DatabaseConnection) Reset() {
+	*x = DatabaseConnection{}
+	mi := &file_grpc_client_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseConnection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseConnection) ProtoMessage() {}
+
+func (x *DatabaseConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_client_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseConnection.ProtoReflect.Descriptor instead.
+func (*DatabaseConnection) Descriptor() ([]byte, []int) {
+	return file_grpc_client_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DatabaseConnection) GetPoolName() string {
+	if x != nil {
+		return x.PoolName
+	}
+	return ""
+}
+
+func (x *DatabaseConnection) GetIdle() int64 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *DatabaseConnection) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *DatabaseConnection) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *DatabaseConnection) GetPendingRequests() int64 {
+	if x != nil {
+		return x.PendingRequests
+	}
+	return 0
+}
+
+func (x *DatabaseConnection) GetWaitTime() float64 {
+	if x != nil {
+		return x.WaitTime
+	}
+	return 0
+}
+
+func (x *DatabaseConnection) GetUseTime() float64 {
+	if x != nil {
+		return x.UseTime
+	}
+	return 0
+}
+
+// 定义 LeakSuspicion 结构体
+type LeakSuspicion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PoolName      string                 `protobuf:"bytes,1,opt,name=poolName,proto3" json:"poolName,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Intervals     int32                  `protobuf:"varint,3,opt,name=intervals,proto3" json:"intervals,omitempty"`
+	Used          int64                  `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`
+	Max           int64                  `protobuf:"varint,5,opt,name=max,proto3" json:"max,omitempty"`
+	WaitTime      float64                `protobuf:"fixed64,6,opt,name=waitTime,proto3" json:"waitTime,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	LeakSuspicious                 []string `protobuf:"bytes,1,rep,name=leakSuspicious,proto3" json:"leakSuspicious,omitempty"`
-	DatabaseConnectionMessageArray []string `protobuf:"bytes,2,rep,name=databaseConnectionMessageArray,proto3" json:"databaseConnectionMessageArray,omitempty"`
+func (x *LeakSuspicion) Reset() {
+	*x = LeakSuspicion{}
+	mi := &file_grpc_client_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeakSuspicion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakSuspicion) ProtoMessage() {}
+
+func (x *LeakSuspicion) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_client_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakSuspicion.ProtoReflect.Descriptor instead.
+func (*LeakSuspicion) Descriptor() ([]byte, []int) {
+	return file_grpc_client_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LeakSuspicion) GetPoolName() string {
+	if x != nil {
+		return x.PoolName
+	}
+	return ""
+}
+
+func (x *LeakSuspicion) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *LeakSuspicion) GetIntervals() int32 {
+	if x != nil {
+		return x.Intervals
+	}
+	return 0
+}
+
+func (x *LeakSuspicion) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *LeakSuspicion) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *LeakSuspicion) GetWaitTime() float64 {
+	if x != nil {
+		return x.WaitTime
+	}
+	return 0
+}
+
+// 定义 DatabaseConnectionMessage 结构体
+type DatabaseConnectionMessage struct {
+	state                          protoimpl.MessageState `protogen:"open.v1"`
+	LeakSuspicious                 []*LeakSuspicion       `protobuf:"bytes,3,rep,name=leakSuspicious,proto3" json:"leakSuspicious,omitempty"`
+	DatabaseConnectionMessageArray []*DatabaseConnection  `protobuf:"bytes,4,rep,name=databaseConnectionMessageArray,proto3" json:"databaseConnectionMessageArray,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
 }
 
 func (x *DatabaseConnectionMessage) Reset() {
 	*x = DatabaseConnectionMessage{}
-	mi := &file_grpc_client_proto_msgTypes[9]
+	mi := &file_grpc_client_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -593,7 +872,7 @@ func (x *DatabaseConnectionMessage) String() string {
 func (*DatabaseConnectionMessage) ProtoMessage() {}
 
 func (x *DatabaseConnectionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[9]
+	mi := &file_grpc_client_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -606,17 +885,17 @@ func (x *DatabaseConnectionMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabaseConnectionMessage.ProtoReflect.Descriptor instead.
 func (*DatabaseConnectionMessage) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{9}
+	return file_grpc_client_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *DatabaseConnectionMessage) GetLeakSuspicious() []string {
+func (x *DatabaseConnectionMessage) GetLeakSuspicious() []*LeakSuspicion {
 	if x != nil {
 		return x.LeakSuspicious
 	}
 	return nil
 }
 
-func (x *DatabaseConnectionMessage) GetDatabaseConnectionMessageArray() []string {
+func (x *DatabaseConnectionMessage) GetDatabaseConnectionMessageArray() []*DatabaseConnection {
 	if x != nil {
 		return x.DatabaseConnectionMessageArray
 	}
@@ -625,17 +904,16 @@ func (x *DatabaseConnectionMessage) GetDatabaseConnectionMessageArray() []string
 
 // 定义 BufferPool 结构体
 type BufferPool struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mapped        *BufferPool_Mapped     `protobuf:"bytes,1,opt,name=mapped,proto3" json:"mapped,omitempty"`
+	Direct        *BufferPool_Direct     `protobuf:"bytes,2,opt,name=direct,proto3" json:"direct,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Mapped *BufferPool_Mapped `protobuf:"bytes,1,opt,name=mapped,proto3" json:"mapped,omitempty"`
-	Direct *BufferPool_Direct `protobuf:"bytes,2,opt,name=direct,proto3" json:"direct,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BufferPool) Reset() {
 	*x = BufferPool{}
-	mi := &file_grpc_client_proto_msgTypes[10]
+	mi := &file_grpc_client_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +925,7 @@ func (x *BufferPool) String() string {
 func (*BufferPool) ProtoMessage() {}
 
 func (x *BufferPool) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[10]
+	mi := &file_grpc_client_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +938,7 @@ func (x *BufferPool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BufferPool.ProtoReflect.Descriptor instead.
 func (*BufferPool) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{10}
+	return file_grpc_client_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *BufferPool) GetMapped() *BufferPool_Mapped {
@@ -677,11 +955,122 @@ func (x *BufferPool) GetDirect() *BufferPool_Direct {
 	return nil
 }
 
-type ExportMetricsServiceRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+// 定义 ClassLoading 结构体
+type ClassLoading struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	LoadedClassCount      int64                  `protobuf:"varint,1,opt,name=loadedClassCount,proto3" json:"loadedClassCount,omitempty"`
+	TotalLoadedClassCount int64                  `protobuf:"varint,2,opt,name=totalLoadedClassCount,proto3" json:"totalLoadedClassCount,omitempty"`
+	UnloadedClassCount    int64                  `protobuf:"varint,3,opt,name=unloadedClassCount,proto3" json:"unloadedClassCount,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ClassLoading) Reset() {
+	*x = ClassLoading{}
+	mi := &file_grpc_client_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
+func (x *ClassLoading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassLoading) ProtoMessage() {}
+
+func (x *ClassLoading) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_client_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassLoading.ProtoReflect.Descriptor instead.
+func (*ClassLoading) Descriptor() ([]byte, []int) {
+	return file_grpc_client_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ClassLoading) GetLoadedClassCount() int64 {
+	if x != nil {
+		return x.LoadedClassCount
+	}
+	return 0
+}
+
+func (x *ClassLoading) GetTotalLoadedClassCount() int64 {
+	if x != nil {
+		return x.TotalLoadedClassCount
+	}
+	return 0
+}
+
+func (x *ClassLoading) GetUnloadedClassCount() int64 {
+	if x != nil {
+		return x.UnloadedClassCount
+	}
+	return 0
+}
+
+// 定义 Compilation 结构体，totalCompilationTime 单位为毫秒
+type Compilation struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TotalCompilationTime int64                  `protobuf:"varint,2,opt,name=totalCompilationTime,proto3" json:"totalCompilationTime,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Compilation) Reset() {
+	*x = Compilation{}
+	mi := &file_grpc_client_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Compilation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Compilation) ProtoMessage() {}
+
+func (x *Compilation) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_client_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Compilation.ProtoReflect.Descriptor instead.
+func (*Compilation) Descriptor() ([]byte, []int) {
+	return file_grpc_client_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Compilation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Compilation) GetTotalCompilationTime() int64 {
+	if x != nil {
+		return x.TotalCompilationTime
+	}
+	return 0
+}
+
+type ExportMetricsServiceRequest struct {
+	state                     protoimpl.MessageState     `protogen:"open.v1"`
 	BufferPool                *BufferPool                `protobuf:"bytes,1,opt,name=bufferPool,proto3" json:"bufferPool,omitempty"`
 	AgentId                   string                     `protobuf:"bytes,2,opt,name=agentId,proto3" json:"agentId,omitempty"`
 	CreationTime              string                     `protobuf:"bytes,3,opt,name=creationTime,proto3" json:"creationTime,omitempty"`
@@ -696,12 +1085,19 @@ type ExportMetricsServiceRequest struct {
 	GarbageCollector          *GarbageCollector          `protobuf:"bytes,12,opt,name=garbageCollector,proto3" json:"garbageCollector,omitempty"`
 	MultiAgentId              string                     `protobuf:"bytes,13,opt,name=multiAgentId,proto3" json:"multiAgentId,omitempty"`
 	DatabaseConnectionMessage *DatabaseConnectionMessage `protobuf:"bytes,14,opt,name=databaseConnectionMessage,proto3" json:"databaseConnectionMessage,omitempty"`
-	Status                    int32                      `protobuf:"varint,15,opt,name=status,proto3" json:"status,omitempty"`
+	// 0 正常，1 警告，2 严重，3 离线
+	Status       int32         `protobuf:"varint,15,opt,name=status,proto3" json:"status,omitempty"`
+	ClassLoading *ClassLoading `protobuf:"bytes,16,opt,name=classLoading,proto3" json:"classLoading,omitempty"`
+	Compilation  *Compilation  `protobuf:"bytes,17,opt,name=compilation,proto3" json:"compilation,omitempty"`
+	// 触发 status 的健康规则名
+	StatusReasons []string `protobuf:"bytes,18,rep,name=statusReasons,proto3" json:"statusReasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExportMetricsServiceRequest) Reset() {
 	*x = ExportMetricsServiceRequest{}
-	mi := &file_grpc_client_proto_msgTypes[11]
+	mi := &file_grpc_client_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -713,7 +1109,7 @@ func (x *ExportMetricsServiceRequest) String() string {
 func (*ExportMetricsServiceRequest) ProtoMessage() {}
 
 func (x *ExportMetricsServiceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[11]
+	mi := &file_grpc_client_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -726,7 +1122,7 @@ func (x *ExportMetricsServiceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportMetricsServiceRequest.ProtoReflect.Descriptor instead.
 func (*ExportMetricsServiceRequest) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{11}
+	return file_grpc_client_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ExportMetricsServiceRequest) GetBufferPool() *BufferPool {
@@ -834,18 +1230,38 @@ func (x *ExportMetricsServiceRequest) GetStatus() int32 {
 	return 0
 }
 
-type ExportMetricsPartialSuccess struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ExportMetricsServiceRequest) GetClassLoading() *ClassLoading {
+	if x != nil {
+		return x.ClassLoading
+	}
+	return nil
+}
+
+func (x *ExportMetricsServiceRequest) GetCompilation() *Compilation {
+	if x != nil {
+		return x.Compilation
+	}
+	return nil
+}
+
+func (x *ExportMetricsServiceRequest) GetStatusReasons() []string {
+	if x != nil {
+		return x.StatusReasons
+	}
+	return nil
+}
 
-	RejectedLogRecords int64  `protobuf:"varint,1,opt,name=RejectedLogRecords,proto3" json:"RejectedLogRecords,omitempty"`
-	ErrorMessage       string `protobuf:"bytes,2,opt,name=ErrorMessage,proto3" json:"ErrorMessage,omitempty"`
+type ExportMetricsPartialSuccess struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	RejectedLogRecords int64                  `protobuf:"varint,1,opt,name=RejectedLogRecords,proto3" json:"RejectedLogRecords,omitempty"`
+	ErrorMessage       string                 `protobuf:"bytes,2,opt,name=ErrorMessage,proto3" json:"ErrorMessage,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *ExportMetricsPartialSuccess) Reset() {
 	*x = ExportMetricsPartialSuccess{}
-	mi := &file_grpc_client_proto_msgTypes[12]
+	mi := &file_grpc_client_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -857,7 +1273,7 @@ func (x *ExportMetricsPartialSuccess) String() string {
 func (*ExportMetricsPartialSuccess) ProtoMessage() {}
 
 func (x *ExportMetricsPartialSuccess) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[12]
+	mi := &file_grpc_client_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -870,7 +1286,7 @@ func (x *ExportMetricsPartialSuccess) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportMetricsPartialSuccess.ProtoReflect.Descriptor instead.
 func (*ExportMetricsPartialSuccess) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{12}
+	return file_grpc_client_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ExportMetricsPartialSuccess) GetRejectedLogRecords() int64 {
@@ -888,18 +1304,17 @@ func (x *ExportMetricsPartialSuccess) GetErrorMessage() string {
 }
 
 type BufferPool_Mapped struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Used          int64                  `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Capacity      int64                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Count    int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
-	Used     int32 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
-	Capacity int32 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BufferPool_Mapped) Reset() {
 	*x = BufferPool_Mapped{}
-	mi := &file_grpc_client_proto_msgTypes[15]
+	mi := &file_grpc_client_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -911,7 +1326,7 @@ func (x *BufferPool_Mapped) String() string {
 func (*BufferPool_Mapped) ProtoMessage() {}
 
 func (x *BufferPool_Mapped) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[15]
+	mi := &file_grpc_client_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -924,24 +1339,24 @@ func (x *BufferPool_Mapped) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BufferPool_Mapped.ProtoReflect.Descriptor instead.
 func (*BufferPool_Mapped) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{10, 0}
+	return file_grpc_client_proto_rawDescGZIP(), []int{13, 0}
 }
 
-func (x *BufferPool_Mapped) GetCount() int32 {
+func (x *BufferPool_Mapped) GetCount() int64 {
 	if x != nil {
 		return x.Count
 	}
 	return 0
 }
 
-func (x *BufferPool_Mapped) GetUsed() int32 {
+func (x *BufferPool_Mapped) GetUsed() int64 {
 	if x != nil {
 		return x.Used
 	}
 	return 0
 }
 
-func (x *BufferPool_Mapped) GetCapacity() int32 {
+func (x *BufferPool_Mapped) GetCapacity() int64 {
 	if x != nil {
 		return x.Capacity
 	}
@@ -949,18 +1364,17 @@ func (x *BufferPool_Mapped) GetCapacity() int32 {
 }
 
 type BufferPool_Direct struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Used          int64                  `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Capacity      int64                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Count    int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
-	Used     int32 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
-	Capacity int32 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *BufferPool_Direct) Reset() {
 	*x = BufferPool_Direct{}
-	mi := &file_grpc_client_proto_msgTypes[16]
+	mi := &file_grpc_client_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -972,7 +1386,7 @@ func (x *BufferPool_Direct) String() string {
 func (*BufferPool_Direct) ProtoMessage() {}
 
 func (x *BufferPool_Direct) ProtoReflect() protoreflect.Message {
-	mi := &file_grpc_client_proto_msgTypes[16]
+	mi := &file_grpc_client_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -985,24 +1399,24 @@ func (x *BufferPool_Direct) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BufferPool_Direct.ProtoReflect.Descriptor instead.
 func (*BufferPool_Direct) Descriptor() ([]byte, []int) {
-	return file_grpc_client_proto_rawDescGZIP(), []int{10, 1}
+	return file_grpc_client_proto_rawDescGZIP(), []int{13, 1}
 }
 
-func (x *BufferPool_Direct) GetCount() int32 {
+func (x *BufferPool_Direct) GetCount() int64 {
 	if x != nil {
 		return x.Count
 	}
 	return 0
 }
 
-func (x *BufferPool_Direct) GetUsed() int32 {
+func (x *BufferPool_Direct) GetUsed() int64 {
 	if x != nil {
 		return x.Used
 	}
 	return 0
 }
 
-func (x *BufferPool_Direct) GetCapacity() int32 {
+func (x *BufferPool_Direct) GetCapacity() int64 {
 	if x != nil {
 		return x.Capacity
 	}
@@ -1011,7 +1425,7 @@ func (x *BufferPool_Direct) GetCapacity() int32 {
 
 var File_grpc_client_proto protoreflect.FileDescriptor
 
-var file_grpc_client_proto_rawDesc = []byte{
+var file_grpc_client_proto_rawDesc = string([]byte{
 	0x0a, 0x11, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
@@ -1035,26 +1449,52 @@ var file_grpc_client_proto_rawDesc = []byte{
 	0x28, 0x01, 0x52, 0x09, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x70, 0x75, 0x12, 0x24, 0x0a,
 	0x0d, 0x61, 0x76, 0x67, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x43, 0x70, 0x75, 0x18, 0x04,
 	0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x61, 0x76, 0x67, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
-	0x43, 0x70, 0x75, 0x22, 0x4b, 0x0a, 0x0b, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66,
-	0x6f, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x73,
-	0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f,
-	0x22, 0xec, 0x01, 0x0a, 0x06, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74,
-	0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a,
-	0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x73,
-	0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x12, 0x38, 0x0a,
-	0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x54, 0x68, 0x72,
-	0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x17,
-	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x54, 0x68, 0x72, 0x65,
-	0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x54,
-	0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e,
-	0x74, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x61, 0x6d, 0x6f, 0x6e, 0x54, 0x68, 0x72, 0x65, 0x61,
-	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x64, 0x65,
-	0x61, 0x6d, 0x6f, 0x6e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22,
+	0x43, 0x70, 0x75, 0x22, 0x8e, 0x02, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x24,
+	0x0a, 0x0d, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x6f, 0x63, 0x6b,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x6f,
+	0x63, 0x6b, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x54, 0x72, 0x61,
+	0x63, 0x65, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x54,
+	0x72, 0x61, 0x63, 0x65, 0x22, 0x5e, 0x0a, 0x0b, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e,
+	0x66, 0x6f, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x2b, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x4a, 0x04,
+	0x08, 0x02, 0x10, 0x03, 0x22, 0xe8, 0x02, 0x0a, 0x06, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x2e, 0x0a, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49,
+	0x6e, 0x66, 0x6f, 0x73, 0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6e, 0x66, 0x6f,
+	0x73, 0x12, 0x38, 0x0a, 0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65,
+	0x64, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64,
+	0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x70,
+	0x65, 0x61, 0x6b, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x70, 0x65, 0x61, 0x6b, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x61, 0x6d, 0x6f, 0x6e, 0x54,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x11, 0x64, 0x65, 0x61, 0x6d, 0x6f, 0x6e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a,
+	0x3e, 0x0a, 0x10, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
 	0x65, 0x0a, 0x0b, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12,
 	0x0a, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x69, 0x6e,
 	0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18,
@@ -1096,144 +1536,207 @@ var file_grpc_client_proto_rawDesc = []byte{
 	0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
 	0x2e, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f,
 	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x22, 0x8b, 0x01, 0x0a, 0x19, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26,
-	0x0a, 0x0e, 0x6c, 0x65, 0x61, 0x6b, 0x53, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6c, 0x65, 0x61, 0x6b, 0x53, 0x75, 0x73, 0x70,
-	0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x12, 0x46, 0x0a, 0x1e, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61,
-	0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x1e,
-	0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x22, 0x84,
-	0x02, 0x0a, 0x0a, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x2a, 0x0a,
-	0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
-	0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x2e, 0x4d, 0x61, 0x70, 0x70, 0x65,
-	0x64, 0x52, 0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x42, 0x75, 0x66, 0x66,
-	0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x52, 0x06, 0x64,
-	0x69, 0x72, 0x65, 0x63, 0x74, 0x1a, 0x4e, 0x0a, 0x06, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12,
-	0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x1a, 0x4e, 0x0a, 0x06, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70,
-	0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0xc5, 0x04, 0x0a, 0x1b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
-	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50,
-	0x6f, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x42, 0x75, 0x66, 0x66,
-	0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f,
-	0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c,
-	0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65,
-	0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x70,
-	0x70, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x61, 0x70, 0x70, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16,
-	0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x43, 0x50,
-	0x55, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x06, 0x74, 0x68, 0x72, 0x65,
-	0x61, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61,
-	0x64, 0x52, 0x06, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x12, 0x2b, 0x0a, 0x0a, 0x6d, 0x65, 0x6d,
-	0x6f, 0x72, 0x79, 0x50, 0x6f, 0x6f, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
-	0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x0a, 0x6d, 0x65, 0x6d, 0x6f,
-	0x72, 0x79, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x12, 0x16, 0x0a, 0x06, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x06, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x10, 0x67, 0x61, 0x72, 0x62,
-	0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x10, 0x67, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f,
-	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x75, 0x6c, 0x74, 0x69,
-	0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d,
-	0x75, 0x6c, 0x74, 0x69, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x58, 0x0a, 0x19, 0x64,
+	0x22, 0xca, 0x01, 0x0a, 0x12, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x6f, 0x6c, 0x4e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6f, 0x6f, 0x6c, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d,
+	0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x28, 0x0a,
+	0x0f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x77, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x75, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xa3, 0x01,
+	0x0a, 0x0d, 0x4c, 0x65, 0x61, 0x6b, 0x53, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x6f, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x6f, 0x6f, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x77, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x19, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x36, 0x0a, 0x0e, 0x6c, 0x65, 0x61, 0x6b, 0x53, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69,
+	0x6f, 0x75, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x4c, 0x65, 0x61, 0x6b,
+	0x53, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x6c, 0x65, 0x61, 0x6b, 0x53,
+	0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x12, 0x5b, 0x0a, 0x1e, 0x64, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1e, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x41, 0x72, 0x72, 0x61, 0x79, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02,
+	0x10, 0x03, 0x22, 0x84, 0x02, 0x0a, 0x0a, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f,
+	0x6c, 0x12, 0x2a, 0x0a, 0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x2e, 0x4d,
+	0x61, 0x70, 0x70, 0x65, 0x64, 0x52, 0x06, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x12, 0x2a, 0x0a,
+	0x06, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x52, 0x06, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x1a, 0x4e, 0x0a, 0x06, 0x4d, 0x61, 0x70,
+	0x70, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x1a, 0x4e, 0x0a, 0x06, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0xa0, 0x01, 0x0a, 0x0c, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x10, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x15, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c,
+	0x6f, 0x61, 0x64, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x6f, 0x61, 0x64,
+	0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x12,
+	0x75, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x75, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x64, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x55, 0x0a, 0x0b,
+	0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x32, 0x0a, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x69, 0x6d, 0x65, 0x22, 0xce, 0x05, 0x0a, 0x1b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72,
+	0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x50, 0x6f, 0x6f, 0x6c,
+	0x12, 0x18, 0x0a, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x70, 0x70, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x61, 0x70, 0x70, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x03,
+	0x63, 0x70, 0x75, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x43, 0x50, 0x55, 0x52,
+	0x03, 0x63, 0x70, 0x75, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x06, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x52,
+	0x06, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x12, 0x2b, 0x0a, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x50, 0x6f, 0x6f, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x4d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x10, 0x67, 0x61, 0x72, 0x62, 0x61, 0x67,
+	0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x52, 0x10, 0x67, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x58, 0x0a, 0x19, 0x64, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x44,
 	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x19, 0x64, 0x61, 0x74, 0x61,
-	0x62, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x71, 0x0a,
-	0x1b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x50, 0x61,
-	0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x2e, 0x0a, 0x12,
-	0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c,
-	0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x32, 0x6f, 0x0a, 0x04, 0x47, 0x72, 0x70, 0x63, 0x12, 0x29, 0x0a, 0x06, 0x45, 0x78, 0x70, 0x6f,
-	0x72, 0x74, 0x12, 0x0e, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x55, 0x6e, 0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65,
-	0x64, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2e, 0x3b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+	0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x19, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x31, 0x0a, 0x0c, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x0c, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x4c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x2e,
+	0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24,
+	0x0a, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18,
+	0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x73, 0x22, 0x71, 0x0a, 0x1b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x12, 0x2e, 0x0a, 0x12, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4c,
+	0x6f, 0x67, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x12, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x6f, 0x0a, 0x04, 0x47, 0x72, 0x70, 0x63, 0x12,
+	0x29, 0x0a, 0x06, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x0e, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x55, 0x6e,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x11, 0x50, 0x01, 0x5a, 0x0a, 0x2e, 0x2e,
+	0x3b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+})
 
 var (
 	file_grpc_client_proto_rawDescOnce sync.Once
-	file_grpc_client_proto_rawDescData = file_grpc_client_proto_rawDesc
+	file_grpc_client_proto_rawDescData []byte
 )
 
 func file_grpc_client_proto_rawDescGZIP() []byte {
 	file_grpc_client_proto_rawDescOnce.Do(func() {
-		file_grpc_client_proto_rawDescData = protoimpl.X.CompressGZIP(file_grpc_client_proto_rawDescData)
+		file_grpc_client_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_grpc_client_proto_rawDesc), len(file_grpc_client_proto_rawDesc)))
 	})
 	return file_grpc_client_proto_rawDescData
 }
 
-var file_grpc_client_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_grpc_client_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_grpc_client_proto_goTypes = []any{
 	(*ExportRequest)(nil),               // 0: ExportRequest
 	(*ExportResponse)(nil),              // 1: ExportResponse
 	(*CPU)(nil),                         // 2: CPU
-	(*ThreadInfos)(nil),                 // 3: ThreadInfos
-	(*Thread)(nil),                      // 4: Thread
-	(*MemoryUsage)(nil),                 // 5: MemoryUsage
-	(*MemoryPool)(nil),                  // 6: MemoryPool
-	(*GarbageCollectorInfo)(nil),        // 7: GarbageCollectorInfo
-	(*GarbageCollector)(nil),            // 8: GarbageCollector
-	(*DatabaseConnectionMessage)(nil),   // 9: DatabaseConnectionMessage
-	(*BufferPool)(nil),                  // 10: BufferPool
-	(*ExportMetricsServiceRequest)(nil), // 11: ExportMetricsServiceRequest
-	(*ExportMetricsPartialSuccess)(nil), // 12: ExportMetricsPartialSuccess
-	nil,                                 // 13: MemoryPool.MemoryUsagesEntry
-	nil,                                 // 14: GarbageCollector.GarbageCollectorsEntry
-	(*BufferPool_Mapped)(nil),           // 15: BufferPool.Mapped
-	(*BufferPool_Direct)(nil),           // 16: BufferPool.Direct
-	(*emptypb.Empty)(nil),               // 17: google.protobuf.Empty
+	(*ThreadInfo)(nil),                  // 3: ThreadInfo
+	(*ThreadInfos)(nil),                 // 4: ThreadInfos
+	(*Thread)(nil),                      // 5: Thread
+	(*MemoryUsage)(nil),                 // 6: MemoryUsage
+	(*MemoryPool)(nil),                  // 7: MemoryPool
+	(*GarbageCollectorInfo)(nil),        // 8: GarbageCollectorInfo
+	(*GarbageCollector)(nil),            // 9: GarbageCollector
+	(*DatabaseConnection)(nil),          // 10: DatabaseConnection
+	(*LeakSuspicion)(nil),               // 11: LeakSuspicion
+	(*DatabaseConnectionMessage)(nil),   // 12: DatabaseConnectionMessage
+	(*BufferPool)(nil),                  // 13: BufferPool
+	(*ClassLoading)(nil),                // 14: ClassLoading
+	(*Compilation)(nil),                 // 15: Compilation
+	(*ExportMetricsServiceRequest)(nil), // 16: ExportMetricsServiceRequest
+	(*ExportMetricsPartialSuccess)(nil), // 17: ExportMetricsPartialSuccess
+	nil,                                 // 18: Thread.StateCountsEntry
+	nil,                                 // 19: MemoryPool.MemoryUsagesEntry
+	nil,                                 // 20: GarbageCollector.GarbageCollectorsEntry
+	(*BufferPool_Mapped)(nil),           // 21: BufferPool.Mapped
+	(*BufferPool_Direct)(nil),           // 22: BufferPool.Direct
+	(*emptypb.Empty)(nil),               // 23: google.protobuf.Empty
 }
 var file_grpc_client_proto_depIdxs = []int32{
-	11, // 0: ExportRequest.orig:type_name -> ExportMetricsServiceRequest
-	11, // 1: ExportResponse.orig:type_name -> ExportMetricsServiceRequest
-	3,  // 2: Thread.threadInfos:type_name -> ThreadInfos
-	13, // 3: MemoryPool.memoryUsages:type_name -> MemoryPool.MemoryUsagesEntry
-	14, // 4: GarbageCollector.garbageCollectors:type_name -> GarbageCollector.GarbageCollectorsEntry
-	15, // 5: BufferPool.mapped:type_name -> BufferPool.Mapped
-	16, // 6: BufferPool.direct:type_name -> BufferPool.Direct
-	10, // 7: ExportMetricsServiceRequest.bufferPool:type_name -> BufferPool
-	2,  // 8: ExportMetricsServiceRequest.cpu:type_name -> CPU
-	4,  // 9: ExportMetricsServiceRequest.thread:type_name -> Thread
-	6,  // 10: ExportMetricsServiceRequest.memoryPool:type_name -> MemoryPool
-	8,  // 11: ExportMetricsServiceRequest.garbageCollector:type_name -> GarbageCollector
-	9,  // 12: ExportMetricsServiceRequest.databaseConnectionMessage:type_name -> DatabaseConnectionMessage
-	5,  // 13: MemoryPool.MemoryUsagesEntry.value:type_name -> MemoryUsage
-	7,  // 14: GarbageCollector.GarbageCollectorsEntry.value:type_name -> GarbageCollectorInfo
-	0,  // 15: Grpc.Export:input_type -> ExportRequest
-	17, // 16: Grpc.Unexported:input_type -> google.protobuf.Empty
-	1,  // 17: Grpc.Export:output_type -> ExportResponse
-	17, // 18: Grpc.Unexported:output_type -> google.protobuf.Empty
-	17, // [17:19] is the sub-list for method output_type
-	15, // [15:17] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	16, // 0: ExportRequest.orig:type_name -> ExportMetricsServiceRequest
+	16, // 1: ExportResponse.orig:type_name -> ExportMetricsServiceRequest
+	3,  // 2: ThreadInfos.threadInfo:type_name -> ThreadInfo
+	4,  // 3: Thread.threadInfos:type_name -> ThreadInfos
+	18, // 4: Thread.stateCounts:type_name -> Thread.StateCountsEntry
+	19, // 5: MemoryPool.memoryUsages:type_name -> MemoryPool.MemoryUsagesEntry
+	20, // 6: GarbageCollector.garbageCollectors:type_name -> GarbageCollector.GarbageCollectorsEntry
+	11, // 7: DatabaseConnectionMessage.leakSuspicious:type_name -> LeakSuspicion
+	10, // 8: DatabaseConnectionMessage.databaseConnectionMessageArray:type_name -> DatabaseConnection
+	21, // 9: BufferPool.mapped:type_name -> BufferPool.Mapped
+	22, // 10: BufferPool.direct:type_name -> BufferPool.Direct
+	13, // 11: ExportMetricsServiceRequest.bufferPool:type_name -> BufferPool
+	2,  // 12: ExportMetricsServiceRequest.cpu:type_name -> CPU
+	5,  // 13: ExportMetricsServiceRequest.thread:type_name -> Thread
+	7,  // 14: ExportMetricsServiceRequest.memoryPool:type_name -> MemoryPool
+	9,  // 15: ExportMetricsServiceRequest.garbageCollector:type_name -> GarbageCollector
+	12, // 16: ExportMetricsServiceRequest.databaseConnectionMessage:type_name -> DatabaseConnectionMessage
+	14, // 17: ExportMetricsServiceRequest.classLoading:type_name -> ClassLoading
+	15, // 18: ExportMetricsServiceRequest.compilation:type_name -> Compilation
+	6,  // 19: MemoryPool.MemoryUsagesEntry.value:type_name -> MemoryUsage
+	8,  // 20: GarbageCollector.GarbageCollectorsEntry.value:type_name -> GarbageCollectorInfo
+	0,  // 21: Grpc.Export:input_type -> ExportRequest
+	23, // 22: Grpc.Unexported:input_type -> google.protobuf.Empty
+	1,  // 23: Grpc.Export:output_type -> ExportResponse
+	23, // 24: Grpc.Unexported:output_type -> google.protobuf.Empty
+	23, // [23:25] is the sub-list for method output_type
+	21, // [21:23] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_grpc_client_proto_init() }
@@ -1245,9 +1748,9 @@ func file_grpc_client_proto_init() {
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_grpc_client_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_grpc_client_proto_rawDesc), len(file_grpc_client_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1256,7 +1759,6 @@ func file_grpc_client_proto_init() {
 		MessageInfos:      file_grpc_client_proto_msgTypes,
 	}.Build()
 	File_grpc_client_proto = out.File
-	file_grpc_client_proto_rawDesc = nil
 	file_grpc_client_proto_goTypes = nil
 	file_grpc_client_proto_depIdxs = nil
 }